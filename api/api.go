@@ -0,0 +1,122 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+//Version is the version of the snapshot written by this tool, and the only
+//version it compares against
+const Version = 1
+
+//Snapshot records the surface JS sees of every module a run bridged, to
+//compare the next run against
+type Snapshot struct {
+	Version int      `json:"version"`
+	Modules []Module `json:"modules"`
+}
+
+//Module is the JS surface of one bridged Go package. Name is the Go
+//package name, which names the JS module and its native module.
+//Errors holds the codes its promises can be rejected with.
+type Module struct {
+	Name      string     `json:"name"`
+	Methods   []Method   `json:"methods,omitempty"`
+	Constants []Constant `json:"constants,omitempty"`
+	Enums     []Enum     `json:"enums,omitempty"`
+	Events    []Event    `json:"events,omitempty"`
+	Errors    []string   `json:"errors,omitempty"`
+}
+
+//Method is a function JS calls. Returns is its TypeScript result type,
+//a Promise unless the method is synchronous.
+type Method struct {
+	Name    string  `json:"name"`
+	Params  []Field `json:"params,omitempty"`
+	Returns string  `json:"returns"`
+}
+
+//Signature is the TypeScript signature of the method, as declared
+func (m Method) Signature() string {
+	params := make([]string, 0, len(m.Params))
+	for _, p := range m.Params {
+		params = append(params, p.Name+": "+p.Type)
+	}
+	return m.Name + "(" + strings.Join(params, ", ") + "): " + m.Returns
+}
+
+//Field is a named value of a TypeScript type, a method parameter or a
+//field of an event
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+//Constant is an exported constant, with its TypeScript type and Go value
+type Constant struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+//Enum is an enum and its values
+type Enum struct {
+	Name   string      `json:"name"`
+	Values []EnumValue `json:"values"`
+}
+
+//EnumValue is the JS key of an enum value, and the literal JS sees
+type EnumValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+//Event is an event emitted to JS by a callback, with the fields it carries
+type Event struct {
+	Name   string  `json:"name"`
+	Fields []Field `json:"fields,omitempty"`
+}
+
+//NewSnapshot returns the snapshot of modules, ordered by name
+func NewSnapshot(modules []Module) Snapshot {
+	s := Snapshot{Version: Version, Modules: append([]Module{}, modules...)}
+	sort.Slice(s.Modules, func(i, j int) bool {
+		return s.Modules[i].Name < s.Modules[j].Name
+	})
+	return s
+}
+
+//Load reads the snapshot at path
+func Load(path string) (Snapshot, error) {
+	s := Snapshot{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return s, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	if s.Version != Version {
+		return s, fmt.Errorf("%s: unsupported API snapshot version %d, expected %d", path, s.Version, Version)
+	}
+	return s, nil
+}
+
+//Write writes the snapshot to path as indented JSON, leaving TypeScript
+//types such as Promise<string> unescaped for review
+func (s Snapshot) Write(path string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(s)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
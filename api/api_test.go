@@ -0,0 +1,147 @@
+package api
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func snapshot() Snapshot {
+	return NewSnapshot([]Module{
+		Module{
+			Name: "hello",
+			Methods: []Method{
+				Method{Name: "greet", Params: []Field{Field{Name: "name", Type: "string"}}, Returns: "Promise<string>"},
+				Method{Name: "count", Params: []Field{Field{Name: "n", Type: "number"}}, Returns: "Promise<void>"},
+				Method{Name: "version", Returns: "string"},
+				Method{Name: "reset", Returns: "Promise<void>"},
+			},
+			Constants: []Constant{
+				Constant{Name: "Version", Type: "string", Value: `"1.0"`},
+				Constant{Name: "Limit", Type: "number", Value: "10"},
+			},
+			Enums: []Enum{Enum{Name: "Status", Values: []EnumValue{
+				EnumValue{Name: "Active", Value: "'Active'"},
+				EnumValue{Name: "Closed", Value: "'Closed'"},
+			}}},
+			Events: []Event{Event{Name: "HelloModule.Counter.OnProgress", Fields: []Field{
+				Field{Name: "n", Type: "number"},
+				Field{Name: "label", Type: "string"},
+			}}},
+			Errors: []string{"E_NOT_FOUND", "Error"},
+		},
+		Module{Name: "legacy"},
+	})
+}
+
+func TestCompare(t *testing.T) {
+	Convey("Given a snapshot", t, func() {
+		old := snapshot()
+		Convey("When it is compared with itself", func() {
+			changes := Compare(old, snapshot())
+			Convey("Then nothing has changed", func() {
+				So(changes, ShouldBeEmpty)
+			})
+		})
+		Convey("When it is compared with a changed API", func() {
+			next := snapshot()
+			hello := &next.Modules[0]
+			hello.Methods[0].Params[0].Type = "number"
+			hello.Methods[1].Params[0].Name = "limit"
+			hello.Methods[2].Returns = "Promise<string>"
+			hello.Methods = append(hello.Methods[:3], Method{Name: "stop", Returns: "Promise<void>"})
+			hello.Constants[0].Value = `"1.1"`
+			hello.Constants[1].Type = "string"
+			hello.Enums[0].Values = []EnumValue{
+				EnumValue{Name: "Active", Value: "'Active'"},
+				EnumValue{Name: "Paused", Value: "'Paused'"},
+			}
+			hello.Events[0].Fields = []Field{
+				Field{Name: "n", Type: "number"},
+				Field{Name: "title", Type: "string"},
+				Field{Name: "total", Type: "number"},
+			}
+			hello.Errors = []string{"E_QUOTA", "Error"}
+			next.Modules = append(next.Modules[:1], Module{Name: "jobs"})
+			changes := Compare(old, next)
+			messages := make([]string, 0, len(changes))
+			for _, c := range changes {
+				messages = append(messages, c.String())
+			}
+			Convey("Then each change is classified", func() {
+				So(messages, ShouldResemble, []string{
+					"breaking hello.greet: parameter name is number instead of string",
+					"compatible hello.count: parameter n renamed to limit",
+					"breaking hello.version: returns Promise<string> instead of string",
+					"breaking hello.reset: method removed",
+					"compatible hello.stop: method added",
+					"compatible hello.Version: value is \"1.1\" instead of \"1.0\"",
+					"breaking hello.Limit: constant is string instead of number",
+					"breaking hello.Status: value Closed removed",
+					"compatible hello.Status: value Paused added",
+					"breaking HelloModule.Counter.OnProgress: field label renamed to title",
+					"compatible HelloModule.Counter.OnProgress: field total added",
+					"breaking hello: error code E_NOT_FOUND removed",
+					"compatible hello: error code E_QUOTA added",
+					"breaking legacy: module removed",
+					"compatible jobs: module added",
+				})
+			})
+		})
+		Convey("When a method takes another parameter", func() {
+			next := snapshot()
+			next.Modules[0].Methods[3].Params = []Field{Field{Name: "force", Type: "boolean"}}
+			changes := Compare(old, next)
+			Convey("Then the change is breaking, as React Native checks the number of arguments", func() {
+				So(len(changes), ShouldEqual, 1)
+				So(changes[0].Breaking, ShouldBeTrue)
+				So(changes[0].Message, ShouldEqual, "takes 1 parameters instead of 0")
+			})
+		})
+		Convey("When an event field is removed", func() {
+			next := snapshot()
+			next.Modules[0].Events[0].Fields = next.Modules[0].Events[0].Fields[1:]
+			changes := Compare(old, next)
+			Convey("Then the other fields are matched by name", func() {
+				So(len(changes), ShouldEqual, 1)
+				So(changes[0].Message, ShouldEqual, "field n removed")
+			})
+		})
+	})
+}
+
+func TestSignature(t *testing.T) {
+	Convey("Given a method", t, func() {
+		m := snapshot().Modules[0].Methods[0]
+		Convey("Then its signature is as declared in TypeScript", func() {
+			So(m.Signature(), ShouldEqual, "greet(name: string): Promise<string>")
+			So(Method{Name: "version", Returns: "string"}.Signature(), ShouldEqual, "version(): string")
+		})
+	})
+}
+
+func TestLoad(t *testing.T) {
+	Convey("Given a snapshot written to a file", t, func() {
+		path := filepath.Join(t.TempDir(), "api.json")
+		So(snapshot().Write(path), ShouldBeNil)
+		Convey("When it is loaded", func() {
+			s, err := Load(path)
+			Convey("Then it is unchanged", func() {
+				So(err, ShouldBeNil)
+				So(s, ShouldResemble, snapshot())
+			})
+		})
+	})
+	Convey("Given a snapshot of another version", t, func() {
+		path := filepath.Join(t.TempDir(), "api.json")
+		So(ioutil.WriteFile(path, []byte(`{"version": 2, "modules": []}`), 0644), ShouldBeNil)
+		Convey("When it is loaded", func() {
+			_, err := Load(path)
+			Convey("Then it is refused", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,242 @@
+package api
+
+import "fmt"
+
+//Change is a difference between two snapshots. Breaking changes can fail JS
+//written against the older snapshot, while compatible changes cannot.
+type Change struct {
+	Symbol   string
+	Message  string
+	Breaking bool
+}
+
+func (c Change) String() string {
+	kind := "compatible"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	return fmt.Sprintf("%s %s: %s", kind, c.Symbol, c.Message)
+}
+
+//changes collects the changes found by Compare, in the order found
+type changes []Change
+
+func (c *changes) breaking(symbol string, format string, args ...interface{}) {
+	*c = append(*c, Change{Symbol: symbol, Message: fmt.Sprintf(format, args...), Breaking: true})
+}
+
+func (c *changes) compatible(symbol string, format string, args ...interface{}) {
+	*c = append(*c, Change{Symbol: symbol, Message: fmt.Sprintf(format, args...)})
+}
+
+//Compare returns the changes from old to next. Symbols are matched by name,
+//so a renamed method or enum shows as one removed and another added.
+func Compare(old Snapshot, next Snapshot) []Change {
+	c := changes{}
+	modules := make(map[string]Module)
+	for _, m := range next.Modules {
+		modules[m.Name] = m
+	}
+	for _, o := range old.Modules {
+		n, ok := modules[o.Name]
+		if !ok {
+			c.breaking(o.Name, "module removed")
+			continue
+		}
+		c.module(o, n)
+	}
+	known := make(map[string]bool)
+	for _, o := range old.Modules {
+		known[o.Name] = true
+	}
+	for _, n := range next.Modules {
+		if !known[n.Name] {
+			c.compatible(n.Name, "module added")
+		}
+	}
+	return c
+}
+
+func (c *changes) module(o Module, n Module) {
+	c.methods(o.Name, o.Methods, n.Methods)
+	c.constants(o.Name, o.Constants, n.Constants)
+	c.enums(o.Name, o.Enums, n.Enums)
+	c.events(o.Name, o.Events, n.Events)
+	codes := make(map[string]bool)
+	for _, code := range n.Errors {
+		codes[code] = true
+	}
+	for _, code := range o.Errors {
+		if !codes[code] {
+			c.breaking(o.Name, "error code %s removed", code)
+		}
+		delete(codes, code)
+	}
+	for _, code := range n.Errors {
+		if codes[code] {
+			c.compatible(o.Name, "error code %s added", code)
+		}
+	}
+}
+
+func (c *changes) methods(module string, old []Method, next []Method) {
+	methods := make(map[string]Method)
+	for _, m := range next {
+		methods[m.Name] = m
+	}
+	for _, o := range old {
+		symbol := module + "." + o.Name
+		n, ok := methods[o.Name]
+		delete(methods, o.Name)
+		if !ok {
+			c.breaking(symbol, "method removed")
+			continue
+		}
+		if len(o.Params) != len(n.Params) {
+			c.breaking(symbol, "takes %d parameters instead of %d", len(n.Params), len(o.Params))
+		} else {
+			c.params(symbol, o.Params, n.Params)
+		}
+		if o.Returns != n.Returns {
+			c.breaking(symbol, "returns %s instead of %s", n.Returns, o.Returns)
+		}
+	}
+	for _, n := range next {
+		if _, ok := methods[n.Name]; ok {
+			c.compatible(module+"."+n.Name, "method added")
+		}
+	}
+}
+
+func (c *changes) constants(module string, old []Constant, next []Constant) {
+	constants := make(map[string]Constant)
+	for _, k := range next {
+		constants[k.Name] = k
+	}
+	for _, o := range old {
+		symbol := module + "." + o.Name
+		n, ok := constants[o.Name]
+		delete(constants, o.Name)
+		switch {
+		case !ok:
+			c.breaking(symbol, "constant removed")
+		case o.Type != n.Type:
+			c.breaking(symbol, "constant is %s instead of %s", n.Type, o.Type)
+		case o.Value != n.Value:
+			c.compatible(symbol, "value is %s instead of %s", n.Value, o.Value)
+		}
+	}
+	for _, n := range next {
+		if _, ok := constants[n.Name]; ok {
+			c.compatible(module+"."+n.Name, "constant added")
+		}
+	}
+}
+
+func (c *changes) enums(module string, old []Enum, next []Enum) {
+	enums := make(map[string]Enum)
+	for _, e := range next {
+		enums[e.Name] = e
+	}
+	for _, o := range old {
+		symbol := module + "." + o.Name
+		n, ok := enums[o.Name]
+		delete(enums, o.Name)
+		if !ok {
+			c.breaking(symbol, "enum removed")
+			continue
+		}
+		values := make(map[string]string)
+		for _, v := range n.Values {
+			values[v.Name] = v.Value
+		}
+		for _, v := range o.Values {
+			value, ok := values[v.Name]
+			delete(values, v.Name)
+			switch {
+			case !ok:
+				c.breaking(symbol, "value %s removed", v.Name)
+			case value != v.Value:
+				c.breaking(symbol, "value %s is %s instead of %s", v.Name, value, v.Value)
+			}
+		}
+		for _, v := range n.Values {
+			if _, ok := values[v.Name]; ok {
+				c.compatible(symbol, "value %s added", v.Name)
+			}
+		}
+	}
+	for _, n := range next {
+		if _, ok := enums[n.Name]; ok {
+			c.compatible(module+"."+n.Name, "enum added")
+		}
+	}
+}
+
+func (c *changes) events(module string, old []Event, next []Event) {
+	events := make(map[string]Event)
+	for _, e := range next {
+		events[e.Name] = e
+	}
+	for _, o := range old {
+		n, ok := events[o.Name]
+		delete(events, o.Name)
+		if !ok {
+			c.breaking(o.Name, "event removed")
+			continue
+		}
+		c.fields(o.Name, o.Fields, n.Fields)
+	}
+	for _, n := range next {
+		if _, ok := events[n.Name]; ok {
+			c.compatible(n.Name, "event added")
+		}
+	}
+}
+
+//params compares parameters by position, as JS passes them, so renaming
+//one is compatible. old and next are the same length.
+func (c *changes) params(symbol string, old []Field, next []Field) {
+	for i, o := range old {
+		n := next[i]
+		switch {
+		case o.Type != n.Type:
+			c.breaking(symbol, "parameter %s is %s instead of %s", o.Name, n.Type, o.Type)
+		case o.Name != n.Name:
+			c.compatible(symbol, "parameter %s renamed to %s", o.Name, n.Name)
+		}
+	}
+}
+
+//fields compares the fields of an event by name, as JS reads them. A field
+//replaced by a next field of the same type at the same position is reported
+//as renamed.
+func (c *changes) fields(symbol string, old []Field, next []Field) {
+	types := make(map[string]string)
+	for _, n := range next {
+		types[n.Name] = n.Type
+	}
+	names := make(map[string]bool)
+	for _, o := range old {
+		names[o.Name] = true
+	}
+	renamed := make(map[string]bool)
+	for i, o := range old {
+		t, ok := types[o.Name]
+		switch {
+		case ok && t != o.Type:
+			c.breaking(symbol, "field %s is %s instead of %s", o.Name, t, o.Type)
+		case ok:
+		case i < len(next) && next[i].Type == o.Type && !names[next[i].Name]:
+			renamed[next[i].Name] = true
+			c.breaking(symbol, "field %s renamed to %s", o.Name, next[i].Name)
+		default:
+			c.breaking(symbol, "field %s removed", o.Name)
+		}
+	}
+	for _, n := range next {
+		if !names[n.Name] && !renamed[n.Name] {
+			c.compatible(symbol, "field %s added", n.Name)
+		}
+	}
+}
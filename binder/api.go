@@ -0,0 +1,74 @@
+package binder
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+//API holds the Java classes of a bound .aar, keyed by fully qualified name
+type API map[string]*Class
+
+//Has identifies whether the class, or its member when member is not blank,
+//is part of the API
+func (a API) Has(class string, member string) bool {
+	c, ok := a[class]
+	if !ok {
+		return false
+	}
+	return member == "" || c.Fields[member] || c.Methods[member]
+}
+
+//ReadAAR reads the classes compiled into the classes.jar of the .aar at path
+func ReadAAR(path string) (API, error) {
+	aar, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer aar.Close()
+	for _, f := range aar.File {
+		if f.Name != "classes.jar" {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		jar, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: classes.jar: %s", path, err.Error())
+		}
+		return readJar(path, jar)
+	}
+	return nil, fmt.Errorf("%s: no classes.jar found", path)
+}
+
+func readJar(path string, jar *zip.Reader) (API, error) {
+	api := make(API)
+	for _, f := range jar.File {
+		if !strings.HasSuffix(f.Name, ".class") {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		c, err := readClass(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %s", path, f.Name, err.Error())
+		}
+		api[c.Name] = c
+	}
+	return api, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
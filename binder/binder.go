@@ -0,0 +1,74 @@
+package binder
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+//Defaults used for options left blank
+const (
+	DefaultCommand = "gomobile"
+	DefaultTarget  = "android"
+	DefaultOutput  = "app/libs/gobridge.aar"
+)
+
+//execCommand creates the gomobile process, replaced by tests with a stub
+var execCommand = exec.Command
+
+//Options configures the gomobile bind run
+type Options struct {
+	//Command is the gomobile executable
+	Command string `json:"command,omitempty"`
+	//Target is passed to -target, android unless set
+	Target string `json:"target,omitempty"`
+	//JavaPkg is passed to -javapkg when set
+	JavaPkg string `json:"javapkg,omitempty"`
+	//Output is the path of the .aar, within the Android project's libs
+	Output string `json:"output,omitempty"`
+}
+
+//WithDefaults returns o with blank options set to their defaults
+func (o Options) WithDefaults() Options {
+	if o.Command == "" {
+		o.Command = DefaultCommand
+	}
+	if o.Target == "" {
+		o.Target = DefaultTarget
+	}
+	if o.Output == "" {
+		o.Output = DefaultOutput
+	}
+	return o
+}
+
+//Args are the arguments gomobile is run with to bind packages
+func (o Options) Args(packages []string) []string {
+	args := []string{"bind", "-target=" + o.Target}
+	if o.JavaPkg != "" {
+		args = append(args, "-javapkg="+o.JavaPkg)
+	}
+	args = append(args, "-o", o.Output)
+	return append(args, packages...)
+}
+
+//Bind runs gomobile bind for packages, writing the .aar to o.Output.
+//The error holds gomobile's output when it fails.
+func Bind(o Options, packages []string) error {
+	o = o.WithDefaults()
+	err := os.MkdirAll(filepath.Dir(o.Output), 0755)
+	if err != nil {
+		return err
+	}
+	cmd := execCommand(o.Command, o.Args(packages)...)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return fmt.Errorf("%s bind failed: %s", o.Command, err.Error())
+	}
+	return fmt.Errorf("%s bind failed: %s: %s", o.Command, err.Error(), bytes.TrimSpace(output))
+}
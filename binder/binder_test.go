@@ -0,0 +1,175 @@
+package binder
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+//stubCommand runs TestHelperProcess in place of gomobile
+func stubCommand(fail bool) func(string, ...string) *exec.Cmd {
+	return func(command string, args ...string) *exec.Cmd {
+		cs := append([]string{"-test.run=TestHelperProcess", "--", command}, args...)
+		cmd := exec.Command(os.Args[0], cs...)
+		cmd.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+		if fail {
+			cmd.Env = append(cmd.Env, "STUB_FAIL=1")
+		}
+		return cmd
+	}
+}
+
+//TestHelperProcess is the stub gomobile, writing an .aar holding the API
+//of the hello package to the -o path
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if os.Getenv("STUB_FAIL") == "1" {
+		fmt.Fprintln(os.Stderr, "gomobile: no Go package in golang.org/x/missing")
+		os.Exit(1)
+	}
+	for i, arg := range args {
+		if arg == "-o" {
+			ioutil.WriteFile(args[i+1], helloAAR(), 0644)
+		}
+	}
+	os.Exit(0)
+}
+
+func helloAAR() []byte {
+	jar := zipFiles(map[string][]byte{
+		"hello/Hello.class":   classFile("hello/Hello", []string{"MaxItems"}, []string{"<init>", "greetings"}),
+		"hello/Counter.class": classFile("hello/Counter", nil, []string{"onProgress", "done"}),
+	})
+	return zipFiles(map[string][]byte{"AndroidManifest.xml": []byte("<manifest/>"), "classes.jar": jar})
+}
+
+func zipFiles(files map[string][]byte) []byte {
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for name, data := range files {
+		f, _ := w.Create(name)
+		f.Write(data)
+	}
+	w.Close()
+	return b.Bytes()
+}
+
+//classFile compiles a minimal class file declaring fields and methods
+func classFile(name string, fields []string, methods []string) []byte {
+	var pool bytes.Buffer
+	entries := 0
+	utf8 := func(s string) int {
+		pool.WriteByte(1)
+		binary.Write(&pool, binary.BigEndian, uint16(len(s)))
+		pool.WriteString(s)
+		entries++
+		return entries
+	}
+	nameIndex := utf8(name)
+	pool.WriteByte(7)
+	binary.Write(&pool, binary.BigEndian, uint16(nameIndex))
+	entries++
+	classIndex := entries
+	//A long constant takes two entries
+	pool.WriteByte(5)
+	pool.Write(make([]byte, 8))
+	entries += 2
+	descriptor := utf8("()V")
+	code := utf8("Code")
+
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint32(0xCAFEBABE))
+	binary.Write(&b, binary.BigEndian, uint32(52))
+	members := make([][]int, 2)
+	for i, names := range [][]string{fields, methods} {
+		for _, n := range names {
+			members[i] = append(members[i], utf8(n))
+		}
+	}
+	binary.Write(&b, binary.BigEndian, uint16(entries+1))
+	b.Write(pool.Bytes())
+	binary.Write(&b, binary.BigEndian, []uint16{1, uint16(classIndex), 0, 0})
+	for _, indexes := range members {
+		binary.Write(&b, binary.BigEndian, uint16(len(indexes)))
+		for _, index := range indexes {
+			binary.Write(&b, binary.BigEndian, []uint16{1, uint16(index), uint16(descriptor), 1, uint16(code)})
+			binary.Write(&b, binary.BigEndian, uint32(2))
+			b.Write([]byte{0, 0})
+		}
+	}
+	binary.Write(&b, binary.BigEndian, uint16(0))
+	return b.Bytes()
+}
+
+func TestArgs(t *testing.T) {
+	Convey("Given options with a java package", t, func() {
+		o := Options{JavaPkg: "com.example.go"}.WithDefaults()
+		Convey("Then gomobile binds every package for android into libs", func() {
+			So(strings.Join(o.Args([]string{"example.com/a", "example.com/b"}), " "), ShouldEqual,
+				"bind -target=android -javapkg=com.example.go -o app/libs/gobridge.aar example.com/a example.com/b")
+		})
+	})
+}
+
+func TestBind(t *testing.T) {
+	defer func() { execCommand = exec.Command }()
+	output := "/tmp/reactgonative/binder/libs/hello.aar"
+	Convey("Given a stub gomobile", t, func() {
+		os.RemoveAll("/tmp/reactgonative/binder")
+		execCommand = stubCommand(false)
+		Convey("When a package is bound", func() {
+			err := Bind(Options{Output: output}, []string{"golang.org/x/mobile/example/bind/hello"})
+			Convey("Then the .aar is written to libs", func() {
+				So(err, ShouldBeNil)
+				_, err = os.Stat(output)
+				So(err, ShouldBeNil)
+			})
+			Convey("And its API can be read", func() {
+				api, err := ReadAAR(output)
+				So(err, ShouldBeNil)
+				So(api.Has("hello.Hello", ""), ShouldBeTrue)
+				So(api.Has("hello.Hello", "greetings"), ShouldBeTrue)
+				So(api.Has("hello.Hello", "MaxItems"), ShouldBeTrue)
+				So(api.Has("hello.Counter", "onProgress"), ShouldBeTrue)
+			})
+			Convey("And missing symbols are not found", func() {
+				api, _ := ReadAAR(output)
+				So(api.Has("hello.Hello", "count"), ShouldBeFalse)
+				So(api.Has("hello.Missing", ""), ShouldBeFalse)
+			})
+		})
+	})
+	Convey("Given a failing gomobile", t, func() {
+		execCommand = stubCommand(true)
+		Convey("When a package is bound", func() {
+			err := Bind(Options{Output: output}, []string{"golang.org/x/missing"})
+			Convey("Then the error holds gomobile's output", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "no Go package in golang.org/x/missing")
+			})
+		})
+	})
+}
+
+func TestReadClass(t *testing.T) {
+	Convey("Given data that is not a class file", t, func() {
+		_, err := readClass([]byte{0xCA, 0xFE})
+		Convey("Then an error is returned", func() {
+			So(err, ShouldEqual, errClassFormat)
+		})
+	})
+}
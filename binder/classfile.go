@@ -0,0 +1,98 @@
+package binder
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+//errClassFormat is returned for data that is not a valid class file
+var errClassFormat = errors.New("invalid class file")
+
+//Class is a Java class of the bound API, with the names of its members
+type Class struct {
+	Name    string
+	Fields  map[string]bool
+	Methods map[string]bool
+}
+
+//classReader reads the big endian values of a class file
+type classReader struct {
+	data []byte
+	err  error
+}
+
+func (r *classReader) next(n int) []byte {
+	if r.err != nil || len(r.data) < n {
+		r.err = errClassFormat
+		return make([]byte, n)
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *classReader) u2() int {
+	return int(binary.BigEndian.Uint16(r.next(2)))
+}
+
+func (r *classReader) u4() int {
+	return int(binary.BigEndian.Uint32(r.next(4)))
+}
+
+//readClass reads the name, fields and methods of the class file data
+func readClass(data []byte) (*Class, error) {
+	r := &classReader{data: data}
+	if r.u4() != 0xCAFEBABE {
+		return nil, errClassFormat
+	}
+	r.next(4) //minor and major version
+	utf8 := make(map[int]string)
+	classes := make(map[int]int)
+	count := r.u2()
+	for i := 1; i < count && r.err == nil; i++ {
+		switch tag := r.next(1)[0]; tag {
+		case 1:
+			utf8[i] = string(r.next(r.u2()))
+		case 7:
+			classes[i] = r.u2()
+		case 8, 16, 19, 20:
+			r.next(2)
+		case 15:
+			r.next(3)
+		case 3, 4, 9, 10, 11, 12, 17, 18:
+			r.next(4)
+		case 5, 6:
+			//Long and double constants take two entries
+			r.next(8)
+			i++
+		default:
+			return nil, errClassFormat
+		}
+	}
+	r.next(2) //access flags
+	c := &Class{
+		Name:    strings.Replace(utf8[classes[r.u2()]], "/", ".", -1),
+		Fields:  make(map[string]bool),
+		Methods: make(map[string]bool),
+	}
+	r.next(2) //super class
+	r.next(2 * r.u2())
+	for _, members := range []map[string]bool{c.Fields, c.Methods} {
+		count = r.u2()
+		for i := 0; i < count && r.err == nil; i++ {
+			r.next(2) //access flags
+			members[utf8[r.u2()]] = true
+			r.next(2) //descriptor
+			attributes := r.u2()
+			for a := 0; a < attributes && r.err == nil; a++ {
+				r.next(2)
+				r.next(r.u4())
+			}
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return c, nil
+}
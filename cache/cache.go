@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+//Version is the version of the cache written by this tool. A cache of any
+//other version is treated as empty.
+const Version = 1
+
+//Cache records, for each Go package generated, the key it was generated
+//with and the files written, so an unchanged package can be skipped
+type Cache struct {
+	Version  int              `json:"version"`
+	Packages map[string]Entry `json:"packages"`
+}
+
+//Entry is the key a package was generated with, and the SHA-256 of each
+//file generated for it, keyed by path
+type Entry struct {
+	Key   string            `json:"key"`
+	Files map[string]string `json:"files"`
+}
+
+//New returns an empty cache
+func New() *Cache {
+	return &Cache{Version: Version, Packages: make(map[string]Entry)}
+}
+
+//Load reads the cache at path. A missing cache, or one of another version,
+//is empty.
+func Load(path string) (*Cache, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := New()
+	err = json.Unmarshal(data, c)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	if c.Version != Version || c.Packages == nil {
+		return New(), nil
+	}
+	return c, nil
+}
+
+//Fresh reports whether pkg was generated with key, and every file generated
+//for it is still as written
+func (c *Cache) Fresh(pkg string, key string) bool {
+	e, ok := c.Packages[pkg]
+	if !ok || e.Key != key || len(e.Files) == 0 {
+		return false
+	}
+	for path, sum := range e.Files {
+		s, err := HashFile(path)
+		if err != nil || s != sum {
+			return false
+		}
+	}
+	return true
+}
+
+//NewEntry returns the entry of a package generated with key, writing files
+func NewEntry(key string, files []string) (Entry, error) {
+	e := Entry{Key: key, Files: make(map[string]string, len(files))}
+	for _, path := range files {
+		sum, err := HashFile(path)
+		if err != nil {
+			return e, err
+		}
+		e.Files[path] = sum
+	}
+	return e, nil
+}
+
+//Record records pkg as generated as described by e
+func (c *Cache) Record(pkg string, e Entry) {
+	c.Packages[pkg] = e
+}
+
+//Save writes the cache to path, leaving the file untouched when it already
+//holds the same cache
+func (c *Cache) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	old, err := ioutil.ReadFile(path)
+	if err == nil && bytes.Equal(old, data) {
+		return nil
+	}
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, 0777)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+//Key returns the hex SHA-256 of parts. Each part is length prefixed, so
+//moving bytes between parts changes the key.
+func Key(parts ...[]byte) string {
+	h := sha256.New()
+	for _, p := range parts {
+		fmt.Fprintf(h, "%d:", len(p))
+		h.Write(p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+//HashFile returns the hex SHA-256 of the file at path
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+//Executable returns the hex SHA-256 of the running binary. The templates are
+//compiled into the tool, so this changes with the tool and its templates.
+func Executable() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return HashFile(path)
+}
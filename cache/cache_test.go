@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCache(t *testing.T) {
+	Convey("Given a package recorded with the files generated for it", t, func() {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "HelloModule.java")
+		So(ioutil.WriteFile(file, []byte("class HelloModule {}\n"), 0644), ShouldBeNil)
+		key := Key([]byte("tool"), []byte("hello"))
+		e, err := NewEntry(key, []string{file})
+		So(err, ShouldBeNil)
+		c := New()
+		c.Record("hello", e)
+		Convey("Then it is fresh for the same key", func() {
+			So(c.Fresh("hello", key), ShouldBeTrue)
+		})
+		Convey("Then it is stale for another key", func() {
+			So(c.Fresh("hello", Key([]byte("tool"), []byte("hello2"))), ShouldBeFalse)
+			So(c.Fresh("other", key), ShouldBeFalse)
+		})
+		Convey("Then it is stale once a file is edited", func() {
+			So(ioutil.WriteFile(file, []byte("class HelloModule { }\n"), 0644), ShouldBeNil)
+			So(c.Fresh("hello", key), ShouldBeFalse)
+		})
+		Convey("Then it is stale once a file is removed", func() {
+			So(os.Remove(file), ShouldBeNil)
+			So(c.Fresh("hello", key), ShouldBeFalse)
+		})
+		Convey("When the cache is saved and loaded", func() {
+			path := filepath.Join(dir, "cache", "cache.json")
+			So(c.Save(path), ShouldBeNil)
+			loaded, err := Load(path)
+			Convey("Then it is unchanged", func() {
+				So(err, ShouldBeNil)
+				So(loaded, ShouldResemble, c)
+				So(loaded.Fresh("hello", key), ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given caches that cannot be used", t, func() {
+		dir := t.TempDir()
+		Convey("Then a missing cache is empty", func() {
+			c, err := Load(filepath.Join(dir, "missing.json"))
+			So(err, ShouldBeNil)
+			So(c.Packages, ShouldBeEmpty)
+		})
+		Convey("Then a cache of another version is empty", func() {
+			path := filepath.Join(dir, "cache.json")
+			So(ioutil.WriteFile(path, []byte(`{"version": 2, "packages": {"hello": {"key": "k"}}}`), 0644), ShouldBeNil)
+			c, err := Load(path)
+			So(err, ShouldBeNil)
+			So(c.Packages, ShouldBeEmpty)
+		})
+		Convey("Then a malformed cache is an error", func() {
+			path := filepath.Join(dir, "cache.json")
+			So(ioutil.WriteFile(path, []byte(`{"version": 1,`), 0644), ShouldBeNil)
+			_, err := Load(path)
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given parts hashed into a key", t, func() {
+		Convey("Then moving bytes between parts changes the key", func() {
+			So(Key([]byte("ab"), []byte("c")), ShouldNotEqual, Key([]byte("a"), []byte("bc")))
+			So(Key([]byte("ab"), []byte("c")), ShouldEqual, Key([]byte("ab"), []byte("c")))
+		})
+	})
+}
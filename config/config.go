@@ -1,16 +1,273 @@
 package config
 
-import "flag"
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"sort"
+	"time"
 
-var flagvar int
+	"github.com/steve-winter/reactgonative/binder"
+	"github.com/steve-winter/reactgonative/diagnostics"
+	"github.com/steve-winter/reactgonative/types"
+)
 
-func init() {
-	flag.IntVar(&flagvar, "flagname", 1234, "help message for flagname")
+//Commands the tool runs. CommandGenerate is the default.
+const (
+	CommandGenerate = "generate"
+	CommandDumpIR   = "dump-ir"
+	CommandWatch    = "watch"
+)
+
+//ReportJSON is the only report format
+const ReportJSON = "json"
+
+//DefaultCache is the cache generate skips unchanged packages with, unless
+//another is configured
+const DefaultCache = ".reactgonative-cache.json"
+
+//Config holds the options for a single run of the tool
+type Config struct {
+	//Command is the command to run, generate, dump-ir or watch
+	Command string
+	//Interval is how often watch checks the packages for changes
+	Interval time.Duration
+	//FromIR is the IR file generate reads the packages from, in place of
+	//parsing Go packages
+	FromIR string
+	//Strict treats warnings as errors when deciding the exit code
+	Strict bool
+	//Format is the diagnostics output format, text or json
+	Format string
+	//Path is the optional JSON configuration file Bridge was loaded from
+	Path string
+	//Register adds the generated packages to the app's MainApplication,
+	//and Unregister removes them
+	Register   bool
+	Unregister bool
+	//Aggregate generates a single ReactPackage for every Go package
+	Aggregate bool
+	//Build runs gomobile bind once the bridge is generated
+	Build bool
+	//Gradle generates bridge.gradle and applies it from the app's build file.
+	//Unregister reverts this too.
+	Gradle bool
+	//API is the API snapshot the bridged surface is compared with and then
+	//recorded to. AcceptBreaking records it despite breaking changes.
+	API            string
+	AcceptBreaking bool
+	//Cache is the file recording what each package was generated from, so
+	//unchanged packages are skipped. Empty regenerates every package.
+	Cache string
+	//Jobs is the most packages parsed, validated or generated at once
+	Jobs int
+	//Report is the format of the report of what generate did written to
+	//stdout, json or empty for none
+	Report string
+	//Packages holds the import paths and patterns of the Go packages to bind
+	Packages []string
+	Bridge   Bridge
+}
+
+//Bridge holds the options read from the JSON configuration file
+type Bridge struct {
+	//Thread is the default thread Go calls run on
+	Thread string `json:"thread,omitempty"`
+	//Enums is how JS sees Go enums, string or number. Strings are the default.
+	Enums string `json:"enums,omitempty"`
+	//Errors maps the start of an error message to the code promises are
+	//rejected with. These take precedence over codes found in the Go source.
+	Errors map[string]string `json:"errors,omitempty"`
+	//PoolSize is the number of threads of the pool shared by every module.
+	//Zero sizes it to the processors of the device.
+	PoolSize int `json:"poolSize,omitempty"`
+	//Bind holds the gomobile bind options used by the build step
+	Bind binder.Options `json:"bind,omitempty"`
+	//Functions holds per function options, keyed by package.Function
+	Functions map[string]Function `json:"functions,omitempty"`
+}
+
+//Function holds the options for a single Go function.
+//Options set here take precedence over directives in the Go source.
+type Function struct {
+	Thread string `json:"thread,omitempty"`
+	//Sync exposes the function as a blocking synchronous method when true,
+	//or as a promise when false. Unset leaves the source directive in place.
+	Sync *bool `json:"sync,omitempty"`
+}
+
+//Parse processes the command line arguments in args, excluding the program
+//name. An optional command comes first, and arguments following the flags
+//are the packages to bind.
+//Usage, flag and configuration errors are written to output.
+func Parse(args []string, output io.Writer) (Config, error) {
+	c := Config{Command: CommandGenerate}
+	if len(args) > 0 && (args[0] == CommandGenerate || args[0] == CommandDumpIR || args[0] == CommandWatch) {
+		c.Command, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("reactgonative", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.BoolVar(&c.Strict, "strict", false, "exit unsuccessfully if any warnings are reported")
+	fs.StringVar(&c.Format, "format", diagnostics.FormatText, "diagnostics output format, text or json")
+	fs.StringVar(&c.Path, "config", "", "path of a JSON configuration file")
+	fs.BoolVar(&c.Register, "register", false, "register the generated packages in the app's MainApplication")
+	fs.BoolVar(&c.Unregister, "unregister", false, "remove the packages registered in the app's MainApplication")
+	fs.BoolVar(&c.Aggregate, "aggregate", false, "generate a single GoBridgePackage registering every module")
+	fs.BoolVar(&c.Build, "build", false, "run gomobile bind and check the bridge against the bound API")
+	fs.BoolVar(&c.Gradle, "gradle", false, "generate bridge.gradle and apply it from the app's build file")
+	fs.StringVar(&c.FromIR, "from-ir", "", "generate from the packages in an IR file instead of Go source")
+	fs.StringVar(&c.API, "api", "", "compare the bridged API with the snapshot at this path, then record it there")
+	fs.BoolVar(&c.AcceptBreaking, "accept-breaking", false, "record the API snapshot despite breaking changes")
+	fs.DurationVar(&c.Interval, "interval", 500*time.Millisecond, "how often watch checks the packages for changes")
+	fs.StringVar(&c.Report, "report", "", "write a report of the packages, functions and files generated to stdout, as json")
+	fs.IntVar(&c.Jobs, "jobs", runtime.NumCPU(), "number of packages processed at once")
+	fs.StringVar(&c.Cache, "cache", DefaultCache, "file recording what each package was generated from, empty to regenerate every package")
+	fs.Usage = func() {
+		fmt.Fprintf(output, "Usage: reactgonative [generate|dump-ir|watch] [flags] [packages]\n")
+		fmt.Fprintf(output, "dump-ir writes the parsed packages to stdout as JSON, for generate -from-ir\n")
+		fmt.Fprintf(output, "watch generates the bridge, then again each time the packages change\n")
+		fs.PrintDefaults()
+	}
+	err := fs.Parse(args)
+	if err != nil {
+		return c, err
+	}
+	c.Packages = fs.Args()
+	err = c.check()
+	if err == nil && c.Path != "" {
+		c.Bridge, err = Load(c.Path)
+	}
+	if err != nil {
+		fmt.Fprintf(output, "%s\n", err.Error())
+	}
+	return c, err
 }
 
-//ParseFlags processes flags for application. Should be called in main
-func ParseFlags() {
-	if !flag.Parsed() {
-		flag.Parse()
+//check returns an error for options that cannot be used together, or
+//values out of range
+func (c Config) check() error {
+	if c.Unregister && (c.Register || c.Gradle) {
+		return fmt.Errorf("unregister cannot be used with register or gradle")
+	}
+	if c.Command == CommandDumpIR && (c.FromIR != "" || c.Register || c.Unregister || c.Gradle || c.Build || c.API != "") {
+		return fmt.Errorf("dump-ir cannot be used with from-ir, register, unregister, gradle, build or api")
+	}
+	if c.Command == CommandWatch && (c.FromIR != "" || c.Unregister) {
+		return fmt.Errorf("watch cannot be used with from-ir or unregister")
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if c.AcceptBreaking && c.API == "" {
+		return fmt.Errorf("accept-breaking needs an api snapshot")
+	}
+	if c.FromIR != "" && len(c.Packages) > 0 {
+		return fmt.Errorf("packages cannot be given with from-ir")
+	}
+	if c.Report != "" && c.Report != ReportJSON {
+		return fmt.Errorf("unknown report %q, expected json", c.Report)
+	}
+	if c.Report != "" && (c.Command != CommandGenerate || c.Unregister) {
+		return fmt.Errorf("report can only be used to generate")
+	}
+	if c.Jobs < 1 {
+		return fmt.Errorf("jobs must be at least 1")
+	}
+	if c.Format != diagnostics.FormatText && c.Format != diagnostics.FormatJSON {
+		return fmt.Errorf("unknown format %q, expected text or json", c.Format)
+	}
+	return nil
+}
+
+//Load reads the JSON configuration file at path
+func Load(path string) (Bridge, error) {
+	b := Bridge{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return b, err
+	}
+	err = json.Unmarshal(data, &b)
+	if err != nil {
+		return b, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	return b, b.validate(path)
+}
+
+func (b Bridge) validate(path string) error {
+	if b.Thread != "" && !types.IsThread(b.Thread) {
+		return fmt.Errorf("%s: unknown thread %q, expected inline, background or pool", path, b.Thread)
+	}
+	if b.PoolSize < 0 {
+		return fmt.Errorf("%s: poolSize must not be negative", path)
+	}
+	if b.Enums != "" && !types.IsEnumRepresentation(b.Enums) {
+		return fmt.Errorf("%s: unknown enums %q, expected string or number", path, b.Enums)
+	}
+	for prefix, code := range b.Errors {
+		if prefix == "" || code == "" {
+			return fmt.Errorf("%s: errors must map a non empty message to a non empty code", path)
+		}
+	}
+	for name, f := range b.Functions {
+		if f.Thread != "" && !types.IsThread(f.Thread) {
+			return fmt.Errorf("%s: %s: unknown thread %q, expected inline, background or pool", path, name, f.Thread)
+		}
+	}
+	return nil
+}
+
+//Apply sets the directives of the functions in g from the configuration.
+//Function options override the Go source, which overrides the defaults.
+//The enums of g take the configured representation, and the configured
+//errors are matched ahead of those in g, longest message first.
+//g is bound under the configured javapkg. Function options setting a
+//thread on a sync function are warned about in diags, as the thread is
+//ignored.
+func (b Bridge) Apply(g *types.GoType, diags *diagnostics.List) {
+	g.JavaPkg = b.Bind.JavaPkg
+	for i := range g.Enums {
+		g.Enums[i].Representation = b.Enums
+	}
+	if len(b.Errors) > 0 {
+		prefixes := make([]string, 0, len(b.Errors))
+		for prefix := range b.Errors {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Slice(prefixes, func(i, j int) bool {
+			if len(prefixes[i]) != len(prefixes[j]) {
+				return len(prefixes[i]) > len(prefixes[j])
+			}
+			return prefixes[i] < prefixes[j]
+		})
+		errs := make([]types.GoError, 0, len(prefixes)+len(g.Errors))
+		for _, prefix := range prefixes {
+			errs = append(errs, types.GoError{Code: b.Errors[prefix], Message: prefix, Prefix: true})
+		}
+		g.Errors = append(errs, g.Errors...)
+	}
+	for i := range g.Functions {
+		f := &g.Functions[i]
+		thread := f.Directives.Thread
+		if f.Directives.Thread == "" {
+			f.Directives.Thread = b.Thread
+		}
+		options, ok := b.Functions[g.PackageName+"."+f.Name]
+		if !ok {
+			continue
+		}
+		if options.Thread != "" {
+			f.Directives.Thread, thread = options.Thread, options.Thread
+		}
+		if options.Sync != nil {
+			f.Directives.Sync = *options.Sync
+		}
+		//Directives setting both are warned about while parsing
+		if f.Directives.Sync && thread != "" && (options.Thread != "" || options.Sync != nil) {
+			diags.Warnf(f.Pos, diagnostics.CodeInvalidDirective,
+				"%s.%s: thread %s is ignored, sync functions run on the calling JS thread", g.PackageName, f.Name, thread)
+		}
 	}
 }
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/binder"
+	"github.com/steve-winter/reactgonative/diagnostics"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+func TestParse(t *testing.T) {
+	Convey("Given no arguments", t, func() {
+		Convey("When parsed", func() {
+			c, err := Parse([]string{}, ioutil.Discard)
+			Convey("Then there is no error", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And strict is off", func() {
+				So(c.Strict, ShouldBeFalse)
+			})
+			Convey("And the format is text", func() {
+				So(c.Format, ShouldEqual, "text")
+			})
+			Convey("And unchanged packages are skipped using the default cache", func() {
+				So(c.Cache, ShouldEqual, DefaultCache)
+			})
+			Convey("And a package is processed on each CPU", func() {
+				So(c.Jobs, ShouldEqual, runtime.NumCPU())
+			})
+		})
+	})
+	Convey("Given strict and json arguments", t, func() {
+		Convey("When parsed", func() {
+			c, err := Parse([]string{"--strict", "--format", "json"}, ioutil.Discard)
+			Convey("Then there is no error", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And strict is on", func() {
+				So(c.Strict, ShouldBeTrue)
+			})
+			Convey("And the format is json", func() {
+				So(c.Format, ShouldEqual, "json")
+			})
+		})
+	})
+	Convey("Given packages after the flags", t, func() {
+		Convey("When parsed", func() {
+			c, err := Parse([]string{"-strict", "example.com/a", "./..."}, ioutil.Discard)
+			Convey("Then they are the packages to bind", func() {
+				So(err, ShouldBeNil)
+				So(c.Packages, ShouldResemble, []string{"example.com/a", "./..."})
+			})
+		})
+	})
+	Convey("Given both register and unregister", t, func() {
+		Convey("When parsed", func() {
+			var output strings.Builder
+			_, err := Parse([]string{"-register", "-unregister"}, &output)
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+			Convey("And it is written to the output", func() {
+				So(output.String(), ShouldEqual, "unregister cannot be used with register or gradle\n")
+			})
+		})
+	})
+	Convey("Given a command before the flags", t, func() {
+		Convey("When parsed", func() {
+			c, err := Parse([]string{"dump-ir", "-strict", "example.com/a"}, ioutil.Discard)
+			Convey("Then it is the command to run", func() {
+				So(err, ShouldBeNil)
+				So(c.Command, ShouldEqual, CommandDumpIR)
+				So(c.Packages, ShouldResemble, []string{"example.com/a"})
+			})
+		})
+		Convey("When no command is given", func() {
+			c, err := Parse([]string{"-from-ir", "bridge.json"}, ioutil.Discard)
+			Convey("Then the command is generate", func() {
+				So(err, ShouldBeNil)
+				So(c.Command, ShouldEqual, CommandGenerate)
+				So(c.FromIR, ShouldEqual, "bridge.json")
+			})
+		})
+	})
+	Convey("Given dump-ir with an option it cannot use", t, func() {
+		Convey("When parsed", func() {
+			_, err := Parse([]string{"dump-ir", "-register"}, ioutil.Discard)
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+	Convey("Given packages along with from-ir", t, func() {
+		Convey("When parsed", func() {
+			_, err := Parse([]string{"generate", "-from-ir", "bridge.json", "example.com/a"}, ioutil.Discard)
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+	Convey("Given accept-breaking without an api snapshot", t, func() {
+		Convey("When parsed", func() {
+			_, err := Parse([]string{"-accept-breaking"}, ioutil.Discard)
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+	Convey("Given the watch command", t, func() {
+		Convey("When parsed", func() {
+			c, err := Parse([]string{"watch", "-interval", "2s", "example.com/a"}, ioutil.Discard)
+			Convey("Then the packages are watched at that interval", func() {
+				So(err, ShouldBeNil)
+				So(c.Command, ShouldEqual, CommandWatch)
+				So(c.Interval, ShouldEqual, 2*time.Second)
+			})
+		})
+		Convey("When parsed with from-ir", func() {
+			_, err := Parse([]string{"watch", "-from-ir", "bridge.json"}, ioutil.Discard)
+			Convey("Then an error is returned, as there are no packages to watch", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+	Convey("Given a report", t, func() {
+		Convey("When parsed", func() {
+			c, err := Parse([]string{"-report", "json"}, ioutil.Discard)
+			Convey("Then it is written as json", func() {
+				So(err, ShouldBeNil)
+				So(c.Report, ShouldEqual, ReportJSON)
+			})
+		})
+		Convey("When parsed with an unknown format", func() {
+			_, err := Parse([]string{"-report", "xml"}, ioutil.Discard)
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+		Convey("When parsed with watch", func() {
+			_, err := Parse([]string{"watch", "-report", "json"}, ioutil.Discard)
+			Convey("Then an error is returned, as watch never finishes", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+	Convey("Given no jobs", t, func() {
+		Convey("When parsed", func() {
+			_, err := Parse([]string{"-jobs", "0"}, ioutil.Discard)
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+	Convey("Given an unknown format", t, func() {
+		Convey("When parsed", func() {
+			_, err := Parse([]string{"-format", "xml"}, ioutil.Discard)
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestLoad(t *testing.T) {
+	Convey("Given a configuration file with thread options", t, func() {
+		path := "/tmp/reactgonative/config_load.json"
+		os.MkdirAll("/tmp/reactgonative", 0777)
+		ioutil.WriteFile(path, []byte(`{"thread": "pool", "functions": {"hello.Greetings": {"thread": "inline"}}}`), 0666)
+		Convey("When it is loaded", func() {
+			b, err := Load(path)
+			Convey("Then there is no error", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the default thread is pool", func() {
+				So(b.Thread, ShouldEqual, "pool")
+			})
+			Convey("And the function thread is inline", func() {
+				So(b.Functions["hello.Greetings"].Thread, ShouldEqual, "inline")
+			})
+		})
+	})
+	Convey("Given a configuration file with an unknown thread", t, func() {
+		path := "/tmp/reactgonative/config_load_bad.json"
+		os.MkdirAll("/tmp/reactgonative", 0777)
+		ioutil.WriteFile(path, []byte(`{"thread": "ui"}`), 0666)
+		Convey("When it is loaded", func() {
+			_, err := Load(path)
+			Convey("Then an error is returned", func() {
+				So(err.Error(), ShouldContainSubstring, `unknown thread "ui"`)
+			})
+		})
+	})
+	Convey("Given a configuration file with a negative pool size", t, func() {
+		path := filepath.Join(t.TempDir(), "config.json")
+		ioutil.WriteFile(path, []byte(`{"poolSize": -1}`), 0666)
+		Convey("When it is loaded", func() {
+			_, err := Load(path)
+			Convey("Then an error is returned", func() {
+				So(err.Error(), ShouldContainSubstring, "poolSize must not be negative")
+			})
+		})
+	})
+	Convey("Given a configuration file with an unknown enum representation", t, func() {
+		path := "/tmp/reactgonative/config_load_enums.json"
+		os.MkdirAll("/tmp/reactgonative", 0777)
+		ioutil.WriteFile(path, []byte(`{"enums": "symbol"}`), 0666)
+		Convey("When it is loaded", func() {
+			_, err := Load(path)
+			Convey("Then an error is returned", func() {
+				So(err.Error(), ShouldContainSubstring, `unknown enums "symbol"`)
+			})
+		})
+	})
+}
+
+func TestApply(t *testing.T) {
+	Convey("Given a go type with a thread directive", t, func() {
+		g := types.GoType{
+			PackageName: "hello",
+			Functions: []types.GoFunction{
+				types.GoFunction{Name: "Greetings"},
+				types.GoFunction{Name: "Count", Directives: types.GoDirectives{Thread: "background"}},
+				types.GoFunction{Name: "Sum", Directives: types.GoDirectives{Thread: "background"}},
+				types.GoFunction{Name: "Version"},
+				types.GoFunction{Name: "Load", Directives: types.GoDirectives{Sync: true}},
+				types.GoFunction{Name: "Pinned", Directives: types.GoDirectives{Thread: "background"}},
+			},
+			Enums:  []types.GoEnum{types.GoEnum{Name: "Status"}},
+			Errors: []types.GoError{types.GoError{Name: "ErrNotFound", Code: "E_NOT_FOUND", Message: "not found"}},
+		}
+		sync, async := true, false
+		b := Bridge{
+			Thread: "pool",
+			Enums:  "number",
+			Bind:   binder.Options{JavaPkg: "com.example.go"},
+			Errors: map[string]string{"timeout": "E_TIMEOUT", "timeout reading": "E_READ"},
+			Functions: map[string]Function{
+				"hello.Sum":     Function{Thread: "inline"},
+				"hello.Version": Function{Sync: &sync},
+				"hello.Load":    Function{Sync: &async},
+				"hello.Pinned":  Function{Sync: &sync},
+			},
+		}
+		Convey("When the configuration is applied", func() {
+			diags := &diagnostics.List{}
+			b.Apply(&g, diags)
+			Convey("Then undirected functions use the default thread", func() {
+				So(g.Functions[0].Thread(), ShouldEqual, "pool")
+			})
+			Convey("And the directive is kept over the default", func() {
+				So(g.Functions[1].Thread(), ShouldEqual, "background")
+			})
+			Convey("And function options override the directive", func() {
+				So(g.Functions[2].Thread(), ShouldEqual, "inline")
+			})
+			Convey("And sync can be enabled by function options", func() {
+				So(g.Functions[3].Directives.Sync, ShouldBeTrue)
+				So(g.Functions[3].Thread(), ShouldEqual, "inline")
+			})
+			Convey("And sync can be disabled by function options", func() {
+				So(g.Functions[4].Directives.Sync, ShouldBeFalse)
+			})
+			Convey("And a thread set on a sync function is warned about", func() {
+				So(g.Functions[5].Thread(), ShouldEqual, "inline")
+				So(diags.Count(diagnostics.Warning), ShouldEqual, 1)
+				So(diags.Diagnostics()[0].Code, ShouldEqual, diagnostics.CodeInvalidDirective)
+				So(diags.Diagnostics()[0].Message, ShouldEqual, "hello.Pinned: thread background is ignored, sync functions run on the calling JS thread")
+			})
+			Convey("And enums take the configured representation", func() {
+				So(g.Enums[0].IsString(), ShouldBeFalse)
+			})
+			Convey("And configured errors are matched first, longest first", func() {
+				So(len(g.Errors), ShouldEqual, 3)
+				So(g.Errors[0].Code, ShouldEqual, "E_READ")
+				So(g.Errors[1].Code, ShouldEqual, "E_TIMEOUT")
+				So(g.Errors[1].Prefix, ShouldBeTrue)
+				So(g.Errors[2].Code, ShouldEqual, "E_NOT_FOUND")
+			})
+		})
+	})
+}
@@ -0,0 +1,26 @@
+package diagnostics
+
+//Stable diagnostic codes. RGN0xx are raised while parsing, RGN1xx while
+//validating, RGN2xx while generating, RGN3xx while integrating with the
+//app and RGN4xx while comparing with the API snapshot. Codes are never reused.
+const (
+	CodeParse            = "RGN001"
+	CodeUnknownDirective = "RGN002"
+	CodeInvalidDirective = "RGN003"
+
+	CodeNotBindable = "RGN100"
+	CodeNotBridged  = "RGN101"
+	CodeIgnored     = "RGN102"
+
+	CodeWrite       = "RGN200"
+	CodeInvalidJava = "RGN201"
+	CodeCache       = "RGN202"
+
+	CodeRegister      = "RGN300"
+	CodeBind          = "RGN301"
+	CodeMissingSymbol = "RGN302"
+
+	CodeBreakingChange   = "RGN400"
+	CodeCompatibleChange = "RGN401"
+	CodeSnapshot         = "RGN402"
+)
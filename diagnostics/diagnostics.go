@@ -0,0 +1,190 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+	"sync"
+)
+
+//Severity is the level of a Diagnostic
+type Severity int
+
+//Severity levels, in increasing order of importance
+const (
+	Note Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Note:
+		return "note"
+	case Warning:
+		return "warning"
+	}
+	return "error"
+}
+
+//MarshalJSON encodes the severity by name
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+//Output formats accepted by Write
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+//Diagnostic is a single warning or error, positioned at the Go source it
+//relates to where possible. Code is stable between releases.
+type Diagnostic struct {
+	Pos      token.Position
+	Severity Severity
+	Code     string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	pos := d.Pos.String()
+	if pos == "-" {
+		return fmt.Sprintf("%s %s: %s", d.Severity, d.Code, d.Message)
+	}
+	return fmt.Sprintf("%s: %s %s: %s", pos, d.Severity, d.Code, d.Message)
+}
+
+type jsonDiagnostic struct {
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+//List collects diagnostics across each stage of a run, and is safe to add
+//to from several goroutines. A nil *List discards everything added to it.
+type List struct {
+	mu    sync.Mutex
+	items []Diagnostic
+}
+
+//Add appends d to the list
+func (l *List) Add(d Diagnostic) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = append(l.items, d)
+}
+
+//Merge appends the diagnostics of o, in the order they were added to it.
+//Work done concurrently collects into a List each, merged in a fixed
+//order so diagnostics without a position are reported deterministically.
+func (l *List) Merge(o *List) {
+	for _, d := range o.added() {
+		l.Add(d)
+	}
+}
+
+//added returns a copy of the diagnostics in the order they were added
+func (l *List) added() []Diagnostic {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Diagnostic{}, l.items...)
+}
+
+//Notef adds a Note at pos
+func (l *List) Notef(pos token.Position, code string, format string, args ...interface{}) {
+	l.Add(Diagnostic{Pos: pos, Severity: Note, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+//Warnf adds a Warning at pos
+func (l *List) Warnf(pos token.Position, code string, format string, args ...interface{}) {
+	l.Add(Diagnostic{Pos: pos, Severity: Warning, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+//Errorf adds an Error at pos
+func (l *List) Errorf(pos token.Position, code string, format string, args ...interface{}) {
+	l.Add(Diagnostic{Pos: pos, Severity: Error, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+//Diagnostics returns the collected diagnostics ordered by file and line.
+//Diagnostics without a position keep the order they were added in, after
+//those with one.
+func (l *List) Diagnostics() []Diagnostic {
+	sorted := l.added()
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Pos, sorted[j].Pos
+		if a.Filename != b.Filename {
+			if a.Filename == "" || b.Filename == "" {
+				return b.Filename == ""
+			}
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return sorted
+}
+
+//Count returns the number of diagnostics of severity s
+func (l *List) Count(s Severity) int {
+	count := 0
+	for _, d := range l.added() {
+		if d.Severity == s {
+			count++
+		}
+	}
+	return count
+}
+
+//Failed identifies whether the run should exit unsuccessfully.
+//Errors always fail, and warnings fail too when strict is set.
+func (l *List) Failed(strict bool) bool {
+	if l.Count(Error) > 0 {
+		return true
+	}
+	return strict && l.Count(Warning) > 0
+}
+
+//Write outputs the diagnostics to w in the given format, either FormatText
+//with one diagnostic per line, or FormatJSON as a single array
+func (l *List) Write(w io.Writer, format string) error {
+	diags := l.Diagnostics()
+	switch format {
+	case FormatJSON:
+		out := make([]jsonDiagnostic, 0, len(diags))
+		for _, d := range diags {
+			out = append(out, jsonDiagnostic{
+				File:     d.Pos.Filename,
+				Line:     d.Pos.Line,
+				Column:   d.Pos.Column,
+				Severity: d.Severity,
+				Code:     d.Code,
+				Message:  d.Message,
+			})
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	case FormatText, "":
+		for _, d := range diags {
+			if _, err := fmt.Fprintln(w, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown diagnostics format %q", format)
+}
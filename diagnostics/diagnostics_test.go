@@ -0,0 +1,104 @@
+package diagnostics
+
+import (
+	"bytes"
+	"go/token"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestList(t *testing.T) {
+	Convey("Given a list with diagnostics out of order", t, func() {
+		l := &List{}
+		l.Errorf(token.Position{}, CodeWrite, "unable to write %s", "Module.java")
+		l.Warnf(token.Position{Filename: "b.go", Line: 3, Column: 1}, CodeNotBridged, "skipped")
+		l.Notef(token.Position{Filename: "a.go", Line: 9, Column: 2}, CodeIgnored, "ignored")
+		Convey("Then they are ordered by position, unpositioned last", func() {
+			d := l.Diagnostics()
+			So(d[0].Pos.Filename, ShouldEqual, "a.go")
+			So(d[1].Pos.Filename, ShouldEqual, "b.go")
+			So(d[2].Code, ShouldEqual, CodeWrite)
+		})
+		Convey("And the counts are per severity", func() {
+			So(l.Count(Note), ShouldEqual, 1)
+			So(l.Count(Warning), ShouldEqual, 1)
+			So(l.Count(Error), ShouldEqual, 1)
+		})
+		Convey("When written as text", func() {
+			buf := &bytes.Buffer{}
+			err := l.Write(buf, FormatText)
+			Convey("Then each diagnostic is a line with position, severity and code", func() {
+				So(err, ShouldBeNil)
+				So(buf.String(), ShouldEqual, "a.go:9:2: note RGN102: ignored\n"+
+					"b.go:3:1: warning RGN101: skipped\n"+
+					"error RGN200: unable to write Module.java\n")
+			})
+		})
+		Convey("When written as json", func() {
+			buf := &bytes.Buffer{}
+			err := l.Write(buf, FormatJSON)
+			Convey("Then the severity is named and the position split out", func() {
+				So(err, ShouldBeNil)
+				So(buf.String(), ShouldContainSubstring, `"file": "a.go"`)
+				So(buf.String(), ShouldContainSubstring, `"line": 9`)
+				So(buf.String(), ShouldContainSubstring, `"severity": "warning"`)
+				So(buf.String(), ShouldContainSubstring, `"code": "RGN200"`)
+			})
+		})
+		Convey("When written in an unknown format", func() {
+			err := l.Write(&bytes.Buffer{}, "xml")
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+	Convey("Given a list with only warnings", t, func() {
+		l := &List{}
+		l.Warnf(token.Position{}, CodeNotBridged, "skipped")
+		Convey("Then it only fails when strict", func() {
+			So(l.Failed(false), ShouldBeFalse)
+			So(l.Failed(true), ShouldBeTrue)
+		})
+	})
+	Convey("Given lists filled concurrently", t, func() {
+		l := &List{}
+		parts := []*List{&List{}, &List{}, &List{}}
+		var wg sync.WaitGroup
+		for i, part := range parts {
+			wg.Add(1)
+			go func(i int, part *List) {
+				defer wg.Done()
+				part.Warnf(token.Position{}, CodeWrite, "first %d", i)
+				part.Warnf(token.Position{}, CodeWrite, "second %d", i)
+				l.Notef(token.Position{}, CodeWrite, "shared %d", i)
+			}(i, part)
+		}
+		wg.Wait()
+		Convey("When they are merged in order", func() {
+			merged := &List{}
+			for _, part := range parts {
+				merged.Merge(part)
+			}
+			Convey("Then the diagnostics keep that order", func() {
+				messages := make([]string, 0)
+				for _, d := range merged.Diagnostics() {
+					messages = append(messages, d.Message)
+				}
+				So(messages, ShouldResemble, []string{"first 0", "second 0", "first 1", "second 1", "first 2", "second 2"})
+			})
+			Convey("And nothing added to the shared list is lost", func() {
+				So(l.Count(Note), ShouldEqual, 3)
+			})
+		})
+	})
+	Convey("Given a nil list", t, func() {
+		var l *List
+		Convey("Then adding is discarded", func() {
+			l.Errorf(token.Position{}, CodeParse, "ignored")
+			So(l.Failed(true), ShouldBeFalse)
+			So(len(l.Diagnostics()), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,51 @@
+package filebuilder
+
+import (
+	"github.com/steve-winter/reactgonative/api"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+//APIMethod returns the method JS calls for the i'th function of g
+func APIMethod(g *types.GoType, i int) api.Method {
+	f := &g.Functions[i]
+	method := api.Method{Name: f.JSName(), Returns: resultType(g, f, &g.Returns[i])}
+	for _, p := range jsParams(g, f) {
+		method.Params = append(method.Params, api.Field{Name: p.Name, Type: tsType(g, p.T)})
+	}
+	return method
+}
+
+//APIModule returns the surface JS sees of the module generated for g, as
+//declared in its TypeScript declarations
+func APIModule(g *types.GoType) api.Module {
+	m := api.Module{Name: g.PackageName}
+	for i := range g.Functions {
+		if !g.IsIgnored(i) {
+			m.Methods = append(m.Methods, APIMethod(g, i))
+		}
+	}
+	for _, c := range g.Constants {
+		m.Constants = append(m.Constants, api.Constant{Name: c.Name, Type: types.GoToTS(c.T), Value: c.Value})
+	}
+	for i := range g.Enums {
+		e := &g.Enums[i]
+		enum := api.Enum{Name: e.Name}
+		for _, v := range e.Values {
+			enum.Values = append(enum.Values, api.EnumValue{Name: e.JSValue(v), Value: enumLiteral(e, v)})
+		}
+		m.Enums = append(m.Enums, enum)
+	}
+	for _, c := range g.Callbacks() {
+		for _, method := range c.Methods {
+			event := api.Event{Name: eventName(g.PackageName, c.Name, method.Name)}
+			for i, p := range method.Params {
+				event.Fields = append(event.Fields, api.Field{Name: paramName(p, i), Type: types.GoToTS(p.T)})
+			}
+			m.Events = append(m.Events, event)
+		}
+	}
+	if hasPromises(g) {
+		m.Errors = errorCodes(g)
+	}
+	return m
+}
@@ -0,0 +1,292 @@
+package filebuilder
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/steve-winter/reactgonative/types"
+)
+
+// DeclarationBuilder is the creator of the TypeScript declarations
+// accompanying the JS wrapper of each native module
+type DeclarationBuilder struct {
+	javaFile *JavaFile
+}
+
+// NewDeclarationBuilder returns a new DeclarationBuilder writing under the JS root.
+// The file is not opened or created at this point.
+func NewDeclarationBuilder(root string) DeclarationBuilder {
+	return DeclarationBuilder{
+		javaFile: NewJavaFile(root, ""),
+	}
+}
+
+//BuildDeclarations generates the TypeScript declarations for the functions in g.
+//Returns the file name created, or an error if a write fails
+func (db *DeclarationBuilder) BuildDeclarations(g *types.GoType) (string, error) {
+	fileName := scriptFileName(db.javaFile.fileName, g.PackageName, ".d.ts")
+	db.javaFile.setFileName(fileName)
+	err := db.javaFile.createFile()
+	if err != nil {
+		return "", err
+	}
+	callbacks := g.Callbacks()
+	imports := make([]string, 0)
+	if len(callbacks) > 0 {
+		imports = append(imports, "{ EmitterSubscription } from 'react-native'")
+	}
+	for _, name := range g.LinkedEnums() {
+		//Enums of other packages are declared alongside their own module
+		imports = append(imports, "* as "+name+" from './"+scriptName(g.Linked[name].PackageName)+"'")
+	}
+	for _, line := range imports {
+		err = db.javaFile.writeImport(line)
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(imports) > 0 {
+		err = db.javaFile.writeBlank(1)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, c := range g.Constants {
+		err = db.javaFile.writeMethodBody("export const " + c.Name + ": " + constantType(c))
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(g.Constants) > 0 {
+		err = db.javaFile.writeBlank(1)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, e := range g.Enums {
+		err = db.buildEnum(&e)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, c := range callbacks {
+		err = db.buildEvents(g.PackageName, c)
+		if err != nil {
+			return "", err
+		}
+	}
+	if hasPromises(g) {
+		err = db.buildErrorCodes(g)
+		if err != nil {
+			return "", err
+		}
+	}
+	for i, f := range g.Functions {
+		if g.IsIgnored(i) {
+			continue
+		}
+		err = db.buildFunction(g, &f, &g.Returns[i])
+		if err != nil {
+			return "", err
+		}
+	}
+	return fileName, nil
+}
+
+//buildEnum declares e as a union of its JS values, and the object holding them
+func (db *DeclarationBuilder) buildEnum(e *types.GoEnum) error {
+	values := make([]string, 0, len(e.Values))
+	for _, v := range e.Distinct() {
+		values = append(values, enumLiteral(e, v))
+	}
+	err := db.javaFile.writeMethodBody("export type " + e.Name + " = " + strings.Join(values, " | "))
+	if err != nil {
+		return err
+	}
+	err = db.javaFile.writeLine("export const " + e.Name + ": {")
+	if err != nil {
+		return err
+	}
+	for _, v := range e.Values {
+		err = db.javaFile.writeMethodBody(e.JSValue(v) + ": " + enumLiteral(e, v))
+		if err != nil {
+			return err
+		}
+	}
+	err = db.javaFile.writeLineN("};")
+	if err != nil {
+		return err
+	}
+	return db.javaFile.writeBlank(1)
+}
+
+func (db *DeclarationBuilder) buildEvents(packageName string, c types.GoTypeSpec) error {
+	for _, m := range c.Methods {
+		if len(m.Params) == 0 {
+			err := db.javaFile.writeMethodBody("export type " + c.Name + m.Name + "Event = Record<string, never>")
+			if err != nil {
+				return err
+			}
+			err = db.javaFile.writeBlank(1)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err := db.javaFile.writeLine("export interface " + c.Name + m.Name + "Event {")
+		if err != nil {
+			return err
+		}
+		for i, p := range m.Params {
+			err = db.javaFile.writeMethodBody(paramName(p, i) + ": " + types.GoToTS(p.T))
+			if err != nil {
+				return err
+			}
+		}
+		err = db.javaFile.writeCloseTag()
+		if err != nil {
+			return err
+		}
+		err = db.javaFile.writeBlank(1)
+		if err != nil {
+			return err
+		}
+	}
+	err := db.javaFile.writeLine("export const " + c.Name + "Events: {")
+	if err != nil {
+		return err
+	}
+	for _, m := range c.Methods {
+		err = db.javaFile.writeMethodBody(m.Name + ": '" + eventName(packageName, c.Name, m.Name) + "'")
+		if err != nil {
+			return err
+		}
+	}
+	err = db.javaFile.writeLineN("};")
+	if err != nil {
+		return err
+	}
+	err = db.javaFile.writeBlank(1)
+	if err != nil {
+		return err
+	}
+	for _, m := range c.Methods {
+		err = db.javaFile.writeMethodBody("export function " + listenerFunctionName(c.Name, m.Name) +
+			"(listener: (event: " + c.Name + m.Name + "Event) => void): EmitterSubscription")
+		if err != nil {
+			return err
+		}
+	}
+	return db.javaFile.writeBlank(1)
+}
+
+func (db *DeclarationBuilder) buildFunction(g *types.GoType, f *types.GoFunction, ret *types.GoParams) error {
+	params := make([]string, 0, len(f.Params))
+	for _, p := range jsParams(g, f) {
+		params = append(params, p.Name+": "+tsType(g, p.T))
+	}
+	return db.javaFile.writeMethodBody("export function " + f.JSName() + "(" + strings.Join(params, ", ") +
+		"): " + resultType(g, f, ret))
+}
+
+//resultType is the TypeScript type a call to f returns. Synchronous
+//functions return ret directly, otherwise a promise resolving to ret.
+func resultType(g *types.GoType, f *types.GoFunction, ret *types.GoParams) string {
+	t := "void"
+	if ret.T != "" {
+		t = tsType(g, ret.T)
+	}
+	if f.Directives.Sync {
+		return t
+	}
+	return "Promise<" + t + ">"
+}
+
+//buildErrorCodes declares the codes promises of the module can be rejected
+//with, and the shape of the rejection
+func (db *DeclarationBuilder) buildErrorCodes(g *types.GoType) error {
+	codes := make([]string, 0)
+	for _, code := range errorCodes(g) {
+		codes = append(codes, tsString(code))
+	}
+	err := db.javaFile.writeMethodBody("export type ErrorCode = " + strings.Join(codes, " | "))
+	if err != nil {
+		return err
+	}
+	err = db.javaFile.writeBlank(1)
+	if err != nil {
+		return err
+	}
+	err = db.javaFile.writeLine("export interface GoError extends Error {")
+	if err != nil {
+		return err
+	}
+	err = db.javaFile.writeMethodBody("code: ErrorCode")
+	if err != nil {
+		return err
+	}
+	err = db.javaFile.writeMethodBody("userInfo?: Record<string, string> | null")
+	if err != nil {
+		return err
+	}
+	err = db.javaFile.writeCloseTag()
+	if err != nil {
+		return err
+	}
+	return db.javaFile.writeBlank(1)
+}
+
+//errorCodes are the codes promises of the module for g can be rejected with
+func errorCodes(g *types.GoType) []string {
+	codes := g.ErrorCodes()
+	if len(g.Enums) > 0 {
+		codes = append(codes, unknownEnumCode)
+	}
+	return append(codes, defaultErrorCode)
+}
+
+//tsString quotes s as a single quoted TypeScript string literal
+func tsString(s string) string {
+	return "'" + strings.NewReplacer("\\", "\\\\", "'", "\\'", "\n", "\\n").Replace(s) + "'"
+}
+
+//tsType is the TypeScript type of the Go type t, naming enums declared by g
+//or, through their import, by linked packages
+func tsType(g *types.GoType, t string) string {
+	if _, ok := g.Enum(t); ok {
+		return t
+	}
+	return types.GoToTS(t)
+}
+
+//constantType is the literal TypeScript type of c where it can be written
+//exactly, falling back to the TypeScript type of the Go type
+func constantType(c types.GoConstant) string {
+	switch c.T {
+	case "bool":
+		return c.Value
+	case "string":
+		if s, err := strconv.Unquote(c.Value); err == nil {
+			if b, err := json.Marshal(s); err == nil {
+				return string(b)
+			}
+		}
+	case "float32", "float64":
+	default:
+		if i, err := strconv.ParseInt(c.Value, 10, 64); err == nil && i <= 1<<53 && i >= -(1<<53) {
+			return c.Value
+		}
+	}
+	return types.GoToTS(c.T)
+}
+
+// Close will close the internal javaFile
+func (db *DeclarationBuilder) Close() error {
+	return db.javaFile.close()
+}
+
+// Output is the file built, once closed
+func (db *DeclarationBuilder) Output() Output {
+	return db.javaFile.output()
+}
@@ -0,0 +1,35 @@
+package filebuilder
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+func TestConstantType(t *testing.T) {
+	Convey("Given constants of each kind", t, func() {
+		Convey("Then exact values are written as literal types", func() {
+			So(constantType(types.GoConstant{T: "string", Value: `"a\tb"`}), ShouldEqual, `"a\tb"`)
+			So(constantType(types.GoConstant{T: "bool", Value: "true"}), ShouldEqual, "true")
+			So(constantType(types.GoConstant{T: "int", Value: "-16"}), ShouldEqual, "-16")
+		})
+		Convey("And other values fall back to their type", func() {
+			So(constantType(types.GoConstant{T: "float64", Value: "3/2"}), ShouldEqual, "number")
+			So(constantType(types.GoConstant{T: "int64", Value: "9223372036854775807"}), ShouldEqual, "number")
+		})
+	})
+}
+
+func TestEnumTypes(t *testing.T) {
+	Convey("Given a go type with an enum", t, func() {
+		e := types.GoEnum{Name: "Status", Underlying: "int", Values: []types.GoConstant{
+			types.GoConstant{Name: "StatusActive", T: "Status", Value: "0"},
+		}}
+		g := &types.GoType{Enums: []types.GoEnum{e}}
+		Convey("Then the enum is named in TypeScript", func() {
+			So(tsType(g, "Status"), ShouldEqual, "Status")
+			So(tsType(g, "int"), ShouldEqual, "number")
+		})
+	})
+}
@@ -0,0 +1,184 @@
+package filebuilder
+
+import (
+	"github.com/steve-winter/reactgonative/types"
+)
+
+// EmitterBuilder is the creator of the Java class implementing a Go callback
+// interface, forwarding each call to JS as a device event
+type EmitterBuilder struct {
+	javaFile *JavaFile
+}
+
+// NewEmitterBuilder returns a new EmitterBuilder containing a JavaFile.
+// The file is not opened or created at this point.
+func NewEmitterBuilder(name string, root string) EmitterBuilder {
+	return EmitterBuilder{
+		javaFile: NewJavaFile(name, root),
+	}
+}
+
+//BuildEmitter generates the emitter class for the interface iface of the Go
+//package g. Returns the className created, or an error if a write fails
+func (eb *EmitterBuilder) BuildEmitter(g *types.GoType, iface types.GoTypeSpec) (string, error) {
+	javaPackage := bridgePackageName(g.PackageName, eb.javaFile.packageRoot)
+	className := emitterClassName(iface.Name)
+	eb.javaFile.setFileName(javaFileName(eb.javaFile.fileName, javaPackage, className))
+	err := eb.javaFile.createFile()
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writePackageLine(javaPackage)
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeBlank(1)
+	if err != nil {
+		return "", err
+	}
+	err = eb.buildImports(g, iface)
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeClassHeader(className, "", iface.Name)
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeBlank(1)
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeMethodBody("private final ReactApplicationContext " + context)
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeBlank(1)
+	if err != nil {
+		return "", err
+	}
+	err = eb.buildConstructor(className)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range iface.Methods {
+		err = eb.buildMethod(g.PackageName, iface.Name, m)
+		if err != nil {
+			return "", err
+		}
+	}
+	err = eb.buildEmit()
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeCloseTag()
+	if err != nil {
+		return "", err
+	}
+	return className, nil
+}
+
+func (eb *EmitterBuilder) buildImports(g *types.GoType, iface types.GoTypeSpec) error {
+	imports := []string{
+		"com.facebook.react.bridge.Arguments",
+		"com.facebook.react.bridge.ReactApplicationContext",
+		"com.facebook.react.bridge.WritableMap",
+		"com.facebook.react.modules.core.DeviceEventManagerModule",
+		goInterfaceName(g.JavaPkg, g.PackageName, iface.Name),
+	}
+	for _, val := range imports {
+		err := eb.javaFile.writeImport(val)
+		if err != nil {
+			return err
+		}
+	}
+	return eb.javaFile.writeBlank(1)
+}
+
+func (eb *EmitterBuilder) buildConstructor(className string) error {
+	params := []types.GoParams{types.GoParams{Name: context, T: "ReactApplicationContext"}}
+	err := eb.javaFile.writeConstructorHeader(className, params)
+	if err != nil {
+		return err
+	}
+	err = eb.javaFile.writeMethodBody("this." + context + " = " + context)
+	if err != nil {
+		return err
+	}
+	err = eb.javaFile.writeCloseTag()
+	if err != nil {
+		return err
+	}
+	return eb.javaFile.writeBlank(1)
+}
+
+func (eb *EmitterBuilder) buildMethod(packageName string, iface string, m types.GoFunction) error {
+	params := make([]types.GoParams, 0, len(m.Params))
+	for i, p := range m.Params {
+		params = append(params, types.GoParams{Name: paramName(p, i), T: p.T})
+	}
+	err := eb.javaFile.writeAnnotation("Override")
+	if err != nil {
+		return err
+	}
+	err = eb.javaFile.writeMethodHeader("void", javaMethodName(m.Name), params)
+	if err != nil {
+		return err
+	}
+	err = eb.javaFile.writeMethodBody("WritableMap event = Arguments.createMap()")
+	if err != nil {
+		return err
+	}
+	for _, p := range params {
+		err = eb.javaFile.writeMethodBody("event." + eventPutter(p.T) + "(\"" + p.Name + "\", " + p.Name + ")")
+		if err != nil {
+			return err
+		}
+	}
+	err = eb.javaFile.writeMethodBody("emit(\"" + eventName(packageName, iface, m.Name) + "\", event)")
+	if err != nil {
+		return err
+	}
+	err = eb.javaFile.writeCloseTag()
+	if err != nil {
+		return err
+	}
+	return eb.javaFile.writeBlank(1)
+}
+
+func (eb *EmitterBuilder) buildEmit() error {
+	params := []types.GoParams{
+		types.GoParams{Name: "eventName", T: "String"},
+		types.GoParams{Name: "event", T: "WritableMap"},
+	}
+	err := eb.javaFile.writePrivateMethodHeader("void", "emit", params)
+	if err != nil {
+		return err
+	}
+	err = eb.javaFile.writeMethodBody(context +
+		".getJSModule(DeviceEventManagerModule.RCTDeviceEventEmitter.class).emit(eventName, event)")
+	if err != nil {
+		return err
+	}
+	return eb.javaFile.writeCloseTag()
+}
+
+// Close will close the internal javaFile
+func (eb *EmitterBuilder) Close() error {
+	return eb.javaFile.close()
+}
+
+// Output is the file built, once closed
+func (eb *EmitterBuilder) Output() Output {
+	return eb.javaFile.output()
+}
+
+//eventPutter is the WritableMap method storing a value of Go type t
+func eventPutter(t string) string {
+	switch t {
+	case "string":
+		return "putString"
+	case "bool":
+		return "putBoolean"
+	}
+	return "putDouble"
+}
@@ -0,0 +1,50 @@
+package filebuilder
+
+import (
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+func TestBuildEmitter(t *testing.T) {
+	Convey("Given a callback interface", t, func() {
+		iface := types.GoTypeSpec{
+			Name: "Counter",
+			Kind: types.KindInterface,
+			Methods: []types.GoFunction{
+				types.GoFunction{Name: "OnProgress", Params: []types.GoParams{
+					types.GoParams{Name: "n", T: "int"},
+					types.GoParams{T: "string"},
+				}},
+			},
+		}
+		Convey("When the emitter is built", func() {
+			eb := NewEmitterBuilder("/tmp/reactgonative/emitter", "com.test")
+			className, err := eb.BuildEmitter(&types.GoType{PackageName: "hello", JavaPkg: "com.example.go"}, iface)
+			So(err, ShouldBeNil)
+			So(eb.Close(), ShouldBeNil)
+			Convey("Then the class is named after the interface", func() {
+				So(className, ShouldEqual, "CounterEmitter")
+			})
+			Convey("And it implements the gomobile interface", func() {
+				content := readEmitter()
+				So(content, ShouldContainSubstring, "import com.example.go.hello.Counter;")
+				So(content, ShouldContainSubstring, "public class CounterEmitter implements Counter {")
+			})
+			Convey("And each method emits a typed event with its params", func() {
+				content := readEmitter()
+				So(content, ShouldContainSubstring, "public void onProgress(long n, String arg1) {")
+				So(content, ShouldContainSubstring, "event.putDouble(\"n\", n);")
+				So(content, ShouldContainSubstring, "event.putString(\"arg1\", arg1);")
+				So(content, ShouldContainSubstring, "emit(\"HelloModule.Counter.OnProgress\", event);")
+			})
+		})
+	})
+}
+
+func readEmitter() string {
+	b, _ := ioutil.ReadFile("/tmp/reactgonative/emitter/com/test/bridge/hello/CounterEmitter.java")
+	return string(b)
+}
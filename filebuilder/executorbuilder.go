@@ -0,0 +1,85 @@
+package filebuilder
+
+import "strconv"
+
+// ExecutorBuilder is the creator of the GoExecutors class, holding the
+// thread pool shared by every module running Go calls on the pool thread
+type ExecutorBuilder struct {
+	javaFile *JavaFile
+}
+
+// NewExecutorBuilder returns a new ExecutorBuilder containing a JavaFile.
+// The file is not opened or created at this point.
+func NewExecutorBuilder(name string, root string) ExecutorBuilder {
+	return ExecutorBuilder{
+		javaFile: NewJavaFile(name, root),
+	}
+}
+
+//executorsClass is the fully qualified name of GoExecutors
+func executorsClass(root string) string {
+	return bridgeRootPackage(root) + ".GoExecutors"
+}
+
+//BuildExecutors generates the GoExecutors class, with a pool of size
+//threads, or one per processor of the device when size is 0.
+//Returns the className created, or an error if a write fails
+func (eb *ExecutorBuilder) BuildExecutors(size int) (string, error) {
+	javaPackage := bridgeRootPackage(eb.javaFile.packageRoot)
+	eb.javaFile.setFileName(javaFileName(eb.javaFile.fileName, javaPackage, "GoExecutors"))
+	err := eb.javaFile.createFile()
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writePackageLine(javaPackage)
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeBlank(1)
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeImport("java.util.concurrent.ExecutorService")
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeImport("java.util.concurrent.Executors")
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeBlank(1)
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeClassHeader("GoExecutors", "", "")
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeBlank(1)
+	if err != nil {
+		return "", err
+	}
+	threads := "Runtime.getRuntime().availableProcessors()"
+	if size > 0 {
+		threads = strconv.Itoa(size)
+	}
+	err = eb.javaFile.writeMethodBody("public static final ExecutorService POOL = Executors.newFixedThreadPool(" + threads + ")")
+	if err != nil {
+		return "", err
+	}
+	err = eb.javaFile.writeCloseTag()
+	if err != nil {
+		return "", err
+	}
+	return "GoExecutors", nil
+}
+
+// Close will close the internal javaFile
+func (eb *ExecutorBuilder) Close() error {
+	return eb.javaFile.close()
+}
+
+// Output is the file built, once closed
+func (eb *ExecutorBuilder) Output() Output {
+	return eb.javaFile.output()
+}
@@ -0,0 +1,38 @@
+package filebuilder
+
+import (
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+//buildExecutors builds GoExecutors with a pool of size threads under root
+//and returns its content
+func buildExecutors(root string, size int) string {
+	eb := NewExecutorBuilder(root, "com.test")
+	_, err := eb.BuildExecutors(size)
+	So(err, ShouldBeNil)
+	So(eb.Close(), ShouldBeNil)
+	b, err := ioutil.ReadFile(eb.Output().Path)
+	So(err, ShouldBeNil)
+	return string(b)
+}
+
+func TestBuildExecutors(t *testing.T) {
+	Convey("Given the pool shared by every module", t, func() {
+		Convey("When it is built without a size", func() {
+			content := buildExecutors(t.TempDir(), 0)
+			Convey("Then it has a thread per processor", func() {
+				So(content, ShouldContainSubstring,
+					"POOL = Executors.newFixedThreadPool(Runtime.getRuntime().availableProcessors());")
+			})
+		})
+		Convey("When it is built with a size", func() {
+			content := buildExecutors(t.TempDir(), 4)
+			Convey("Then it has that many threads", func() {
+				So(content, ShouldContainSubstring, "POOL = Executors.newFixedThreadPool(4);")
+			})
+		})
+	})
+}
@@ -1,22 +1,49 @@
 package filebuilder
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 
+	"github.com/steve-winter/reactgonative/javasyntax"
 	"github.com/steve-winter/reactgonative/types"
 )
 
-//JavaFile represents a file pointer and functions to create a Java class
+//FileStatus is what writing a generated file did to it
+type FileStatus string
+
+//File statuses. A file rewritten with the content it had is unchanged.
+const (
+	FileCreated   FileStatus = "created"
+	FileChanged   FileStatus = "changed"
+	FileUnchanged FileStatus = "unchanged"
+)
+
+//Output is a file a builder wrote, and what writing it did
+type Output struct {
+	Path   string
+	Status FileStatus
+}
+
+//JavaFile represents a file's content and functions to create a Java class.
+//The content is buffered and only written when the file is closed.
 type JavaFile struct {
-	f            *os.File
+	content      *bytes.Buffer
 	fileName     string
 	packageRoot  string
 	depth        int
 	shouldIndent bool
+	//existed is set when the file was there before it was created, with
+	//previous holding its content. status is what writing it did, once
+	//closed.
+	existed  bool
+	previous []byte
+	status   FileStatus
 }
 
 //NewJavaFile creates a new uninitialized JavaFile
@@ -29,7 +56,7 @@ func NewJavaFile(name string, root string) (javaFile *JavaFile) {
 }
 
 func (jf *JavaFile) setFileName(name string) error {
-	if jf.f != nil {
+	if jf.content != nil {
 		return errors.New("File already open")
 	}
 	jf.fileName = name
@@ -42,11 +69,19 @@ func (jf *JavaFile) createFile() error {
 	if err != nil {
 		return err
 	}
-	f, err := os.Create(jf.fileName)
-	if err != nil {
-		return err
+	jf.existed, jf.previous = false, nil
+	info, err := os.Stat(jf.fileName)
+	if err == nil && info.IsDir() {
+		return &os.PathError{Op: "open", Path: jf.fileName, Err: syscall.EISDIR}
+	}
+	if err == nil && info.Mode().IsRegular() {
+		jf.previous, err = ioutil.ReadFile(jf.fileName)
+		if err != nil {
+			return err
+		}
+		jf.existed = true
 	}
-	jf.f = f
+	jf.content = new(bytes.Buffer)
 	return nil
 }
 
@@ -87,6 +122,10 @@ func (jf *JavaFile) writeMethodHeader(returnType string, methodName string, para
 	return jf.writeLine("public " + returnType + " " + methodName + "(" + jf.methodParams(params) + ") {")
 }
 
+func (jf *JavaFile) writePrivateMethodHeader(returnType string, methodName string, params []types.GoParams) error {
+	return jf.writeLine("private " + returnType + " " + methodName + "(" + jf.methodParams(params) + ") {")
+}
+
 func (jf *JavaFile) writeMethodBody(body string) error {
 	return jf.writeLineN(body + ";")
 }
@@ -196,10 +235,10 @@ func (jf *JavaFile) shouldStep(line string) {
 }
 
 func (jf *JavaFile) writeLineFlat(line string) error {
-	_, err := jf.f.WriteString(line + "\n")
-	if err == nil {
-		err = jf.f.Sync()
+	if jf.content == nil {
+		return os.ErrInvalid
 	}
+	_, err := jf.content.WriteString(line + "\n")
 	return err
 }
 
@@ -208,11 +247,76 @@ func (jf *JavaFile) writeBlank(num int) error {
 	for i := 0; i < num; i++ {
 		newLine = newLine + "\n"
 	}
-	_, err := jf.f.WriteString(newLine)
+	if jf.content == nil {
+		return os.ErrInvalid
+	}
+	_, err := jf.content.WriteString(newLine)
 	return err
 }
 
+//close checks the syntax of Java files, then writes the file. Malformed
+//output fails with a *javasyntax.Error positioned in the file, which is
+//left as it was. A file with the content it had is not rewritten, so
+//Gradle and Metro do not rebuild it.
 func (jf *JavaFile) close() error {
+	if jf.content == nil {
+		return os.ErrInvalid
+	}
+	if filepath.Ext(jf.fileName) == ".java" {
+		err := javasyntax.Check(jf.content.Bytes())
+		if e, ok := err.(*javasyntax.Error); ok {
+			e.File = jf.fileName
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return jf.settle()
+}
+
+//settle writes the content when it differs from what the file had,
+//recording what writing it did
+func (jf *JavaFile) settle() error {
+	switch {
+	case !jf.existed:
+		jf.status = FileCreated
+	case bytes.Equal(jf.content.Bytes(), jf.previous):
+		jf.status = FileUnchanged
+		return nil
+	default:
+		jf.status = FileChanged
+	}
+	return writeFileAtomic(jf.fileName, jf.content.Bytes())
+}
+
+//writeFileAtomic writes content to a temporary file beside name and renames
+//it over name, so a failed write never leaves name truncated
+func writeFileAtomic(name string, content []byte) error {
+	dir, base := filepath.Split(name)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := ioutil.TempFile(dir, "."+base+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(content)
+	if err == nil {
+		err = tmp.Chmod(0644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
 
-	return jf.f.Close()
+//output is the file written, once closed
+func (jf *JavaFile) output() Output {
+	return Output{Path: jf.fileName, Status: jf.status}
 }
@@ -2,10 +2,14 @@ package filebuilder
 
 import (
 	"bufio"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/javasyntax"
 	"github.com/steve-winter/reactgonative/types"
 )
 
@@ -26,7 +30,7 @@ func TestNewJavaFile(t *testing.T) {
 				So(jf.shouldIndent, ShouldEqual, false)
 			})
 			Convey("And file is nil", func() {
-				So(jf.f, ShouldEqual, nil)
+				So(jf.content, ShouldBeNil)
 			})
 		})
 	})
@@ -68,11 +72,8 @@ func TestCreateFile(t *testing.T) {
 			Convey("Then no error is generated", func() {
 				So(err, ShouldEqual, nil)
 			})
-			Convey("And the file is set", func() {
-				So(jf.f, ShouldNotEqual, nil)
-			})
-			Convey("And the filename of file is same as JavaFile", func() {
-				So(jf.f.Name(), ShouldEqual, jf.fileName)
+			Convey("And the content is buffered", func() {
+				So(jf.content, ShouldNotBeNil)
 			})
 			Convey("When the same file is created", func() {
 				sf := NewJavaFile("/tmp/reactgonative/testfile_createFile1", "testFileRoot")
@@ -86,6 +87,7 @@ func TestCreateFile(t *testing.T) {
 	Convey("Given file created", t, func() {
 		cf := NewJavaFile("/tmp/reactgonative/testfile_createFileCreated", "testFileRoot")
 		cf.createFile()
+		cf.close()
 		Convey("When a new file is created using same directory", func() {
 			jf := NewJavaFile("/tmp/reactgonative/testfile_createFileCreated/createFile2", "testFileRoot")
 			err := jf.createFile()
@@ -107,6 +109,64 @@ func TestCreateFile(t *testing.T) {
 	})
 }
 
+func TestClose(t *testing.T) {
+	Convey("Given a file written a day ago", t, func() {
+		name := filepath.Join(t.TempDir(), "bridge.js")
+		So(ioutil.WriteFile(name, []byte("first\n"), 0644), ShouldBeNil)
+		dayAgo := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+		So(os.Chtimes(name, dayAgo, dayAgo), ShouldBeNil)
+		jf := NewJavaFile(name, "")
+		So(jf.createFile(), ShouldBeNil)
+		Convey("When it is rewritten with the same content", func() {
+			So(jf.writeLineFlat("first"), ShouldBeNil)
+			So(jf.close(), ShouldBeNil)
+			Convey("Then it keeps its modification time", func() {
+				info, err := os.Stat(name)
+				So(err, ShouldBeNil)
+				So(info.ModTime().Equal(dayAgo), ShouldBeTrue)
+			})
+			Convey("And it is reported as unchanged", func() {
+				So(jf.output(), ShouldResemble, Output{Path: name, Status: FileUnchanged})
+			})
+		})
+		Convey("When it is rewritten with other content", func() {
+			So(jf.writeLineFlat("second"), ShouldBeNil)
+			So(jf.close(), ShouldBeNil)
+			Convey("Then its modification time is updated", func() {
+				info, err := os.Stat(name)
+				So(err, ShouldBeNil)
+				So(info.ModTime().After(dayAgo), ShouldBeTrue)
+			})
+			Convey("And it is reported as changed", func() {
+				So(jf.output().Status, ShouldEqual, FileChanged)
+			})
+			Convey("And it is replaced without leaving a temporary file", func() {
+				content, err := ioutil.ReadFile(name)
+				So(err, ShouldBeNil)
+				So(string(content), ShouldEqual, "second\n")
+				entries, err := ioutil.ReadDir(filepath.Dir(name))
+				So(err, ShouldBeNil)
+				So(len(entries), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func TestOutput(t *testing.T) {
+	Convey("Given a file that does not exist", t, func() {
+		name := filepath.Join(t.TempDir(), "bridge.js")
+		jf := NewJavaFile(name, "")
+		Convey("When it is written", func() {
+			So(jf.createFile(), ShouldBeNil)
+			So(jf.writeLineFlat("first"), ShouldBeNil)
+			So(jf.close(), ShouldBeNil)
+			Convey("Then it is reported as created", func() {
+				So(jf.output(), ShouldResemble, Output{Path: name, Status: FileCreated})
+			})
+		})
+	})
+}
+
 func TestWritePackageLine(t *testing.T) {
 	Convey("Given Javafile object created", t, func() {
 		cf := NewJavaFile("/tmp/reactgonative/testfile_writePackageLine1", "testFileRoot")
@@ -123,6 +183,7 @@ func TestWritePackageLine(t *testing.T) {
 				So(err, ShouldEqual, nil)
 			})
 			Convey("And the end line of the file is packageName", func() {
+				So(cf.close(), ShouldBeNil)
 				So(readLastLines("/tmp/reactgonative/testfile_writePackageLine1", 1)[0],
 					ShouldEqual, "package packageName;")
 			})
@@ -140,6 +201,7 @@ func TestWriteImport(t *testing.T) {
 				So(err, ShouldEqual, nil)
 			})
 			Convey("And the end line of the file is packageName", func() {
+				So(cf.close(), ShouldBeNil)
 				So(readLastLines("/tmp/reactgonative/testfile_writeImport1", 1)[0],
 					ShouldEqual, "import com.lemonade.pink;")
 			})
@@ -157,6 +219,7 @@ func TestWriteClassHeader(t *testing.T) {
 				So(err, ShouldEqual, nil)
 			})
 			Convey("And the end line of the file is the class header", func() {
+				So(cf.close(), ShouldBeNil)
 				So(readLastLines("/tmp/reactgonative/testfile_writeClassHeader1", 1)[0],
 					ShouldEqual, "public class MyClassName extends ExtendsName implements InterfaceName {")
 			})
@@ -168,6 +231,7 @@ func TestWriteClassHeader(t *testing.T) {
 				So(err, ShouldEqual, nil)
 			})
 			Convey("And the end line of the file is the class header", func() {
+				So(cf.close(), ShouldBeNil)
 				So(readLastLines("/tmp/reactgonative/testfile_writeClassHeader1", 1)[0],
 					ShouldEqual, "public class MyClassName extends ExtendsName {")
 			})
@@ -179,6 +243,7 @@ func TestWriteClassHeader(t *testing.T) {
 				So(err, ShouldEqual, nil)
 			})
 			Convey("And the end line of the file is the class header", func() {
+				So(cf.close(), ShouldBeNil)
 				So(readLastLines("/tmp/reactgonative/testfile_writeClassHeader1", 1)[0],
 					ShouldEqual, "public class MyClassName implements InterfaceName {")
 			})
@@ -196,6 +261,7 @@ func TestWriteConstructorHeader(t *testing.T) {
 				So(err, ShouldEqual, nil)
 			})
 			Convey("And the end line of the file is the constructor header", func() {
+				So(cf.close(), ShouldBeNil)
 				So(readLastLines("/tmp/reactgonative/testfile_writeConstructorHeader1", 1)[0],
 					ShouldEqual, "public MyClassName() {")
 			})
@@ -210,6 +276,7 @@ func TestWriteConstructorHeader(t *testing.T) {
 				So(err, ShouldEqual, nil)
 			})
 			Convey("And the end line of the file is the constructor header", func() {
+				So(cf.close(), ShouldBeNil)
 				So(readLastLines("/tmp/reactgonative/testfile_writeConstructorHeader1", 1)[0],
 					ShouldEqual, "public MyClassName(long param1, String param2) {")
 			})
@@ -227,6 +294,7 @@ func TestWriteSuper(t *testing.T) {
 				So(err, ShouldEqual, nil)
 			})
 			Convey("And the end line of the file is the super line", func() {
+				So(cf.close(), ShouldBeNil)
 				So(readLastLines("/tmp/reactgonative/testfile_writeSuper1", 1)[0],
 					ShouldEqual, "super();")
 			})
@@ -238,6 +306,7 @@ func TestWriteSuper(t *testing.T) {
 				So(err, ShouldEqual, nil)
 			})
 			Convey("And the end line of the file is the super line", func() {
+				So(cf.close(), ShouldBeNil)
 				So(readLastLines("/tmp/reactgonative/testfile_writeSuper1", 1)[0],
 					ShouldEqual, "super(params);")
 			})
@@ -258,3 +327,43 @@ func readLastLines(fileName string, lineCount int) []string {
 	}
 	return lines
 }
+
+func TestJavaFileSyntax(t *testing.T) {
+	Convey("Given a Java file with a malformed method header", t, func() {
+		path := filepath.Join(t.TempDir(), "Broken.java")
+		jf := NewJavaFile(path, "")
+		So(jf.createFile(), ShouldBeNil)
+		jf.writeLine("class Broken {")
+		jf.writeMethodHeader("void", "greet", []types.GoParams{types.GoParams{T: "string"}})
+		jf.writeCloseTag()
+		jf.writeCloseTag()
+		Convey("When it is closed", func() {
+			err := jf.close()
+			Convey("Then the syntax error is positioned in the file", func() {
+				syntax, ok := err.(*javasyntax.Error)
+				So(ok, ShouldBeTrue)
+				So(syntax.File, ShouldEqual, path)
+				So(syntax.Line, ShouldEqual, 2)
+			})
+			Convey("And the file is not written", func() {
+				_, err := os.Stat(path)
+				So(os.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given a valid Java file rewritten with malformed content", t, func() {
+		path := filepath.Join(t.TempDir(), "Hello.java")
+		So(ioutil.WriteFile(path, []byte("class Hello {\n}\n"), 0644), ShouldBeNil)
+		jf := NewJavaFile(path, "")
+		So(jf.createFile(), ShouldBeNil)
+		jf.writeLine("class Hello {")
+		Convey("When it is closed", func() {
+			So(jf.close(), ShouldNotBeNil)
+			Convey("Then the file keeps its content", func() {
+				content, err := ioutil.ReadFile(path)
+				So(err, ShouldBeNil)
+				So(string(content), ShouldEqual, "class Hello {\n}\n")
+			})
+		})
+	})
+}
@@ -7,7 +7,16 @@ import (
 	"github.com/steve-winter/reactgonative/types"
 )
 
-var context = "reactContext"
+//context names the ReactApplicationContext in generated constructors
+const context = "reactContext"
+
+//defaultErrorCode is the code promises are rejected with when a Go error
+//does not match any known error
+const defaultErrorCode = "Error"
+
+//unknownEnumCode is the code promises are rejected with when an enum value
+//is not one declared in Go
+const unknownEnumCode = "E_UNKNOWN_ENUM"
 
 // ModuleBuilder is the creator of each Classes boilerplate
 type ModuleBuilder struct {
@@ -73,6 +82,16 @@ func (mb *ModuleBuilder) BuildModule(g *types.GoType) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if g.UsesThread(types.ThreadBackground) {
+		err = mb.javaFile.writeMethodBody("private final ExecutorService executor = Executors.newSingleThreadExecutor()")
+		if err != nil {
+			return "", err
+		}
+		err = mb.javaFile.writeBlank(1)
+		if err != nil {
+			return "", err
+		}
+	}
 	err = mb.buildConstructor(g)
 	if err != nil {
 		return "", err
@@ -87,10 +106,40 @@ func (mb *ModuleBuilder) BuildModule(g *types.GoType) (string, error) {
 		return "", err
 	}
 
-	err = mb.buildReactMethods(&g.Functions, &g.Returns, g.PackageName)
+	if len(g.Constants) > 0 {
+		err = mb.buildGetConstants(g)
+		if err != nil {
+			return "", err
+		}
+	}
+	err = mb.buildReactMethods(g)
 	if err != nil {
 		return "", err
 	}
+	for _, e := range g.Enums {
+		err = mb.buildEnumConverters(&e)
+		if err != nil {
+			return "", err
+		}
+	}
+	if hasPromises(g) {
+		err = mb.buildRejectGoError(g)
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(g.Callbacks()) > 0 {
+		err = mb.buildListenerMethods()
+		if err != nil {
+			return "", err
+		}
+	}
+	if g.UsesThread(types.ThreadBackground) {
+		err = mb.buildShutdown()
+		if err != nil {
+			return "", err
+		}
+	}
 	err = mb.javaFile.writeCloseTag()
 	if err != nil {
 		return "", err
@@ -147,10 +196,55 @@ func (mb *ModuleBuilder) buildImports(g *types.GoType) error {
 	if err != nil {
 		return err
 	}
-	err = mb.javaFile.writeImport(mb.goImport(g.PackageName))
+	if len(g.Errors) > 0 && hasPromises(g) {
+		err = mb.javaFile.writeImport("com.facebook.react.bridge.Arguments")
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeImport("com.facebook.react.bridge.WritableMap")
+		if err != nil {
+			return err
+		}
+	}
+	if len(g.Constants) > 0 {
+		err = mb.javaFile.writeImport("java.util.HashMap")
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeImport("java.util.Map")
+		if err != nil {
+			return err
+		}
+	}
+	if g.UsesThread(types.ThreadBackground) {
+		err = mb.javaFile.writeImport("java.util.concurrent.ExecutorService")
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeImport("java.util.concurrent.Executors")
+		if err != nil {
+			return err
+		}
+	}
+	if g.UsesThread(types.ThreadPool) {
+		err = mb.javaFile.writeImport(executorsClass(mb.javaFile.packageRoot))
+		if err != nil {
+			return err
+		}
+	}
+	err = mb.javaFile.writeImport(mb.goImport(g))
 	if err != nil {
 		return err
 	}
+	for _, name := range g.LinkedEnums() {
+		//Enums of other packages are converted by their own module
+		linked := g.Linked[name]
+		err = mb.javaFile.writeImport(bridgePackageName(linked.PackageName, mb.javaFile.packageRoot) + "." +
+			moduleClassName(linked.PackageName))
+		if err != nil {
+			return err
+		}
+	}
 	err = mb.javaFile.writeBlank(1)
 	if err != nil {
 		return err
@@ -158,9 +252,8 @@ func (mb *ModuleBuilder) buildImports(g *types.GoType) error {
 	return nil
 }
 
-func (mb *ModuleBuilder) goImport(packageName string) string {
-	lowerPackage := strings.ToLower(packageName)
-	return lowerPackage + "." + strings.Title(lowerPackage)
+func (mb *ModuleBuilder) goImport(g *types.GoType) string {
+	return goClassName(g.JavaPkg, g.PackageName)
 }
 
 // Close will close the internal javaFile
@@ -168,6 +261,11 @@ func (mb *ModuleBuilder) Close() error {
 	return mb.javaFile.close()
 }
 
+// Output is the file built, once closed
+func (mb *ModuleBuilder) Output() Output {
+	return mb.javaFile.output()
+}
+
 func (mb *ModuleBuilder) create() error {
 	return mb.javaFile.createFile()
 }
@@ -199,9 +297,45 @@ func (mb *ModuleBuilder) buildGetName(g *types.GoType) error {
 	return nil
 }
 
-func (mb *ModuleBuilder) buildReactMethods(g *[]types.GoFunction, ret *[]types.GoParams, pkgName string) error {
-	for i, val := range *g {
-		err := mb.buildReactMethod(&val, &(*ret)[i], pkgName)
+//buildGetConstants exposes the constants of g to JS, read from the static
+//fields gomobile generates on the package class
+func (mb *ModuleBuilder) buildGetConstants(g *types.GoType) error {
+	err := mb.javaFile.writeAnnotation("Override")
+	if err != nil {
+		return err
+	}
+	err = mb.javaFile.writeMethodHeader("Map<String, Object>", "getConstants", nil)
+	if err != nil {
+		return err
+	}
+	err = mb.javaFile.writeMethodBody("final Map<String, Object> constants = new HashMap<>()")
+	if err != nil {
+		return err
+	}
+	for _, c := range g.Constants {
+		err = mb.javaFile.writeMethodBody("constants.put(\"" + c.Name + "\", " +
+			goSimpleClassName(g.PackageName) + "." + c.Name + ")")
+		if err != nil {
+			return err
+		}
+	}
+	err = mb.javaFile.writeReturnDynamic("constants")
+	if err != nil {
+		return err
+	}
+	err = mb.javaFile.writeCloseTag()
+	if err != nil {
+		return err
+	}
+	return mb.javaFile.writeBlank(1)
+}
+
+func (mb *ModuleBuilder) buildReactMethods(g *types.GoType) error {
+	for i, val := range g.Functions {
+		if g.IsIgnored(i) {
+			continue
+		}
+		err := mb.buildReactMethod(g, &val, &g.Returns[i])
 		if err != nil {
 			return err
 		}
@@ -209,17 +343,24 @@ func (mb *ModuleBuilder) buildReactMethods(g *[]types.GoFunction, ret *[]types.G
 	return nil
 }
 
-func (mb *ModuleBuilder) buildReactMethod(g *types.GoFunction, ret *types.GoParams, pkgName string) error {
+func (mb *ModuleBuilder) buildReactMethod(t *types.GoType, g *types.GoFunction, ret *types.GoParams) error {
+	if g.Directives.Sync {
+		return mb.buildSyncMethod(t, g, ret)
+	}
 	err := mb.javaFile.writeAnnotation("ReactMethod")
 
 	if err != nil {
 		return err
 	}
-	err = mb.buildMethodHeader(g, ret)
+	err = mb.buildMethodHeader(t, g)
+	if err != nil {
+		return err
+	}
+	err = mb.buildEnumParams(t, g, false)
 	if err != nil {
 		return err
 	}
-	err = mb.buildReactMethodBody(g, ret, pkgName)
+	err = mb.buildReactMethodBody(t, g, ret)
 	if err != nil {
 		return err
 	}
@@ -235,24 +376,220 @@ func (mb *ModuleBuilder) buildReactMethod(g *types.GoFunction, ret *types.GoPara
 	return nil
 }
 
-func (mb *ModuleBuilder) buildReactMethodBody(g *types.GoFunction, ret *types.GoParams, pkgName string) error {
-	return mb.wrapTryCatch(func() error {
-		return mb.methodMain(g, ret, pkgName)
-	}, g, ret, "promise.reject(\"Error\")")
+//buildListenerMethods adds the methods NativeEventEmitter requires of a
+//module emitting events. Subscriptions are tracked on the JS side.
+func (mb *ModuleBuilder) buildListenerMethods() error {
+	listeners := map[string]types.GoParams{
+		"addListener":     types.GoParams{Name: "eventName", T: "String"},
+		"removeListeners": types.GoParams{Name: "count", T: "double"},
+	}
+	for _, name := range []string{"addListener", "removeListeners"} {
+		err := mb.javaFile.writeAnnotation("ReactMethod")
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeMethodHeader("void", name, []types.GoParams{listeners[name]})
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeLineN("//Required by NativeEventEmitter")
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeCloseTag()
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeBlank(1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (mb *ModuleBuilder) methodMain(g *types.GoFunction, ret *types.GoParams, pkgName string) error {
+func (mb *ModuleBuilder) buildReactMethodBody(t *types.GoType, g *types.GoFunction, ret *types.GoParams) error {
+	return mb.dispatch(g.Thread(), func() error {
+		return mb.wrapTryCatch(func() error {
+			return mb.methodMain(t, g, ret)
+		}, g, ret, "promise.reject(\"Error\")")
+	})
+}
 
-	methodCall := mb.importedPackageName(pkgName) + "." + strings.ToLower(g.Name) + "(" + mb.buildMethodCallParams(&g.Params) + ")"
-	if ret.T != "" {
-		methodCall = types.GoToJava(ret.T) + " returnParam1 = " + methodCall
+//dispatch writes body to run on thread. The promise is only settled from
+//within body, so it is resolved or rejected exactly once on that thread.
+func (mb *ModuleBuilder) dispatch(thread string, body func() error) error {
+	executor := ""
+	switch thread {
+	case types.ThreadBackground:
+		executor = "executor"
+	case types.ThreadPool:
+		executor = "GoExecutors.POOL"
+	default:
+		return body()
+	}
+	err := mb.javaFile.writeLine(executor + ".execute(() -> {")
+	if err != nil {
+		return err
+	}
+	err = body()
+	if err != nil {
+		return err
+	}
+	return mb.javaFile.writeLineN("});")
+}
+
+//buildShutdown stops the module's executor when React Native tears the
+//module down
+func (mb *ModuleBuilder) buildShutdown() error {
+	err := mb.javaFile.writeAnnotation("Override")
+	if err != nil {
+		return err
+	}
+	err = mb.javaFile.writeMethodHeader("void", "onCatalystInstanceDestroy", nil)
+	if err != nil {
+		return err
 	}
-	err := mb.javaFile.writeMethodBody(methodCall)
+	err = mb.javaFile.writeMethodBody("executor.shutdown()")
 	if err != nil {
 		return err
 	}
+	err = mb.javaFile.writeCloseTag()
+	if err != nil {
+		return err
+	}
+	return mb.javaFile.writeBlank(1)
+}
+
+//buildSyncMethod writes a blocking method returning the Go result directly
+//to JS. Go errors are rethrown, surfacing as JS exceptions.
+func (mb *ModuleBuilder) buildSyncMethod(t *types.GoType, g *types.GoFunction, ret *types.GoParams) error {
+	err := mb.javaFile.writeAnnotation("ReactMethod(isBlockingSynchronousMethod = true)")
+	if err != nil {
+		return err
+	}
+	returnType := "void"
 	if ret.T != "" {
-		err = mb.javaFile.writeMethodBody("promise.resolve(returnParam1)")
+		returnType = reactType(t.BridgeType(ret.T))
+	}
+	err = mb.javaFile.writeMethodHeader(returnType, g.JSName(), bridgeParams(t, jsParams(t, g)))
+	if err != nil {
+		return err
+	}
+	err = mb.buildEnumParams(t, g, true)
+	if err != nil {
+		return err
+	}
+	body := func() error {
+		call := mb.goCall(t, g)
+		if ret.T == "" {
+			return mb.javaFile.writeMethodBody(call)
+		}
+		result, err := mb.buildResult(t, ret, call, true)
+		if err != nil {
+			return err
+		}
+		return mb.javaFile.writeReturnDynamic(result)
+	}
+	if g.ReturnsError() {
+		err = mb.javaFile.writeTry()
+		if err != nil {
+			return err
+		}
+		err = body()
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeCatch("throw new RuntimeException(e)")
+	} else {
+		err = body()
+	}
+	if err != nil {
+		return err
+	}
+	err = mb.javaFile.writeCloseTag()
+	if err != nil {
+		return err
+	}
+	return mb.javaFile.writeBlank(1)
+}
+
+//goCall is the call of the gomobile generated method for g
+func (mb *ModuleBuilder) goCall(t *types.GoType, g *types.GoFunction) string {
+	return goSimpleClassName(t.PackageName) + "." + goMethodName(g) + "(" + mb.buildMethodCallParams(t, &g.Params) + ")"
+}
+
+func (mb *ModuleBuilder) methodMain(t *types.GoType, g *types.GoFunction, ret *types.GoParams) error {
+
+	methodCall := mb.goCall(t, g)
+	if ret.T == "" {
+		err := mb.javaFile.writeMethodBody(methodCall)
+		if err != nil {
+			return err
+		}
+		return mb.javaFile.writeMethodBody("promise.resolve(null)")
+	}
+	result, err := mb.buildResult(t, ret, methodCall, false)
+	if err != nil {
+		return err
+	}
+	return mb.javaFile.writeMethodBody("promise.resolve(" + result + ")")
+}
+
+//buildResult stores the result of call, converting enums to their JS value.
+//Returns the expression of the value to hand back to JS, cast to its
+//reactType.
+func (mb *ModuleBuilder) buildResult(t *types.GoType, ret *types.GoParams, call string, sync bool) (string, error) {
+	e, ok := t.Enum(ret.T)
+	if !ok {
+		result := "returnParam1"
+		if widened(ret.T) {
+			result = "(" + reactType(ret.T) + ") " + result
+		}
+		return result, mb.javaFile.writeMethodBody(types.GoToJava(ret.T) + " returnParam1 = " + call)
+	}
+	err := mb.javaFile.writeMethodBody(types.GoToJava(e.Underlying) + " returnValue1 = " + call)
+	if err != nil {
+		return "", err
+	}
+	value := enumConverter(t, ret.T, "ToString") + "(returnValue1)"
+	if !e.IsString() {
+		return "(double) returnValue1", mb.buildEnumCheck(value+" == null", e.Name, "returnValue1", sync)
+	}
+	err = mb.javaFile.writeMethodBody("String returnParam1 = " + value)
+	if err != nil {
+		return "", err
+	}
+	return "returnParam1", mb.buildEnumCheck("returnParam1 == null", e.Name, "returnValue1", sync)
+}
+
+//buildEnumParams converts each enum parameter of g from its JS value,
+//failing the call when the value is not one declared in Go. Number enums
+//arrive as a double, which must hold a whole number.
+func (mb *ModuleBuilder) buildEnumParams(t *types.GoType, g *types.GoFunction, sync bool) error {
+	for _, p := range jsParams(t, g) {
+		e, ok := t.Enum(p.T)
+		if !ok {
+			continue
+		}
+		if !e.IsString() {
+			underlying := types.GoToJava(e.Underlying)
+			err := mb.javaFile.writeMethodBody("final " + underlying + " " + p.Name + "Value = (" + underlying + ") " + p.Name)
+			if err != nil {
+				return err
+			}
+			err = mb.buildEnumCheck(p.Name+"Value != "+p.Name+" || "+enumConverter(t, p.T, "ToString")+"("+p.Name+"Value) == null", e.Name, p.Name, sync)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err := mb.javaFile.writeMethodBody("final Long " + p.Name + "Value = " +
+			enumConverter(t, p.T, "FromString") + "(" + p.Name + ")")
+		if err != nil {
+			return err
+		}
+		err = mb.buildEnumCheck(p.Name+"Value == null", e.Name, p.Name, sync)
 		if err != nil {
 			return err
 		}
@@ -260,14 +597,119 @@ func (mb *ModuleBuilder) methodMain(g *types.GoFunction, ret *types.GoParams, pk
 	return nil
 }
 
-func (mb *ModuleBuilder) buildMethodCallParams(g *[]types.GoParams) string {
+//buildEnumCheck fails the call when cond holds, naming the unknown value.
+//Promises are rejected with unknownEnumCode, synchronous methods throw.
+func (mb *ModuleBuilder) buildEnumCheck(cond string, enum string, value string, sync bool) error {
+	message := "\"Unknown " + enum + " value: \" + " + value
+	err := mb.javaFile.writeLine("if (" + cond + ") {")
+	if err != nil {
+		return err
+	}
+	if sync {
+		err = mb.javaFile.writeMethodBody("throw new IllegalArgumentException(" + message + ")")
+	} else {
+		err = mb.javaFile.writeMethodBody("promise.reject(\"" + unknownEnumCode + "\", " + message + ")")
+		if err == nil {
+			err = mb.javaFile.writeMethodBody("return")
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return mb.javaFile.writeCloseTag()
+}
+
+//buildEnumConverters writes the helpers converting e between the Go value
+//and its JS value. Unknown values convert to null. They are public for the
+//modules of packages using e.
+func (mb *ModuleBuilder) buildEnumConverters(e *types.GoEnum) error {
+	values := e.Distinct()
+	err := mb.javaFile.writeMethodHeader("static String", enumConverterName(e.Name, "ToString"),
+		[]types.GoParams{types.GoParams{Name: "value", T: "int64"}})
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		err = mb.javaFile.writeLine("if (value == " + v.Value + "L) {")
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeReturnDynamic("\"" + e.JSValue(v) + "\"")
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeCloseTag()
+		if err != nil {
+			return err
+		}
+	}
+	err = mb.javaFile.writeReturnDynamic("null")
+	if err != nil {
+		return err
+	}
+	err = mb.javaFile.writeCloseTag()
+	if err != nil {
+		return err
+	}
+	err = mb.javaFile.writeBlank(1)
+	if err != nil {
+		return err
+	}
+	if !e.IsString() {
+		return nil
+	}
+	err = mb.javaFile.writeMethodHeader("static Long", enumConverterName(e.Name, "FromString"),
+		[]types.GoParams{types.GoParams{Name: "value", T: "string"}})
+	if err != nil {
+		return err
+	}
+	for _, v := range e.Values {
+		err = mb.javaFile.writeLine("if (\"" + e.JSValue(v) + "\".equals(value)) {")
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeReturnDynamic(v.Value + "L")
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeCloseTag()
+		if err != nil {
+			return err
+		}
+	}
+	err = mb.javaFile.writeReturnDynamic("null")
+	if err != nil {
+		return err
+	}
+	err = mb.javaFile.writeCloseTag()
+	if err != nil {
+		return err
+	}
+	return mb.javaFile.writeBlank(1)
+}
+
+func (mb *ModuleBuilder) buildMethodCallParams(t *types.GoType, g *[]types.GoParams) string {
 	paramsMap := mb.paramsToMap(*g)
 	resp := ""
-	for _, val := range paramsMap {
+	for i, val := range paramsMap {
 		if len(resp) != 0 {
 			resp = resp + ", "
 		}
-		resp = resp + val.Name
+		if t.IsCallback(val.T) {
+			//Callbacks are implemented by the bridge, emitting events to JS
+			resp = resp + "new " + emitterClassName(val.T) + "(getReactApplicationContext())"
+			continue
+		}
+		if _, ok := t.Enum(val.T); ok {
+			//Converted from the JS value by buildEnumParams
+			resp = resp + paramName(val, i) + "Value"
+			continue
+		}
+		if widened(val.T) {
+			//Passed from JS as a double
+			resp = resp + "(" + types.GoToJava(val.T) + ") "
+		}
+		resp = resp + paramName(val, i)
 	}
 	return resp
 }
@@ -281,14 +723,90 @@ func (mb *ModuleBuilder) wrapTryCatch(body func() error, g *types.GoFunction, re
 	if err != nil {
 		return err
 	}
-	return mb.javaFile.writeCatch("promise.reject(\"Error\", e)")
+	return mb.javaFile.writeCatch("rejectGoError(promise, e)")
 }
 
-func (mb *ModuleBuilder) buildMethodHeader(g *types.GoFunction, ret *types.GoParams) error {
-	params := make([]types.GoParams, 0)
-	params = append(params, *ret)
+//hasPromises identifies whether any bridged function in g settles a promise
+func hasPromises(g *types.GoType) bool {
+	for i, f := range g.Functions {
+		if !g.IsIgnored(i) && !f.Directives.Sync {
+			return true
+		}
+	}
+	return false
+}
+
+//buildRejectGoError writes the helper rejecting a promise with the code of
+//the Go error e. The error's message is matched against the errors of g in
+//order, and userInfo names the Go error matched.
+func (mb *ModuleBuilder) buildRejectGoError(g *types.GoType) error {
+	err := mb.javaFile.writePrivateMethodHeader("static void", "rejectGoError", []types.GoParams{
+		types.GoParams{Name: "promise", T: "Promise"},
+		types.GoParams{Name: "e", T: "Exception"},
+	})
+	if err != nil {
+		return err
+	}
+	err = mb.javaFile.writeMethodBody("final String message = e.getMessage() == null ? \"\" : e.getMessage()")
+	if err != nil {
+		return err
+	}
+	for _, goErr := range g.Errors {
+		err = mb.javaFile.writeLine("if (" + errorCondition(goErr) + ") {")
+		if err != nil {
+			return err
+		}
+		reject := "promise.reject(" + javaString(goErr.Code) + ", message, e)"
+		if goErr.Name != "" {
+			err = mb.javaFile.writeMethodBody("final WritableMap userInfo = Arguments.createMap()")
+			if err != nil {
+				return err
+			}
+			err = mb.javaFile.writeMethodBody("userInfo.putString(\"goError\", " + javaString(goErr.Name) + ")")
+			if err != nil {
+				return err
+			}
+			reject = "promise.reject(" + javaString(goErr.Code) + ", message, e, userInfo)"
+		}
+		err = mb.javaFile.writeMethodBody(reject)
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeMethodBody("return")
+		if err != nil {
+			return err
+		}
+		err = mb.javaFile.writeCloseTag()
+		if err != nil {
+			return err
+		}
+	}
+	err = mb.javaFile.writeMethodBody("promise.reject(\"" + defaultErrorCode + "\", message, e)")
+	if err != nil {
+		return err
+	}
+	err = mb.javaFile.writeCloseTag()
+	if err != nil {
+		return err
+	}
+	return mb.javaFile.writeBlank(1)
+}
+
+//errorCondition is the Java condition matching message against e.
+//Sentinel errors also match when wrapped with a prefix by fmt.Errorf.
+func errorCondition(e types.GoError) string {
+	if e.Prefix {
+		return "message.startsWith(" + javaString(e.Message) + ")"
+	}
+	return "message.equals(" + javaString(e.Message) + ") || message.endsWith(" + javaString(": "+e.Message) + ")"
+}
+
+//buildMethodHeader declares the promise method for g, taking the params
+//passed from JS followed by the promise
+func (mb *ModuleBuilder) buildMethodHeader(t *types.GoType, g *types.GoFunction) error {
+	params := bridgeParams(t, jsParams(t, g))
 	params = append(params, types.GoParams{Name: "promise", T: "Promise"})
-	return mb.javaFile.writeMethodHeader("void", strings.ToLower(g.Name), params)
+	return mb.javaFile.writeMethodHeader("void", g.JSName(), params)
 }
 
 func (mb *ModuleBuilder) paramsToMap(params []types.GoParams) []types.GoParams {
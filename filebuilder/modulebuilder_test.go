@@ -0,0 +1,108 @@
+package filebuilder
+
+import (
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+//buildModule builds the module of g under root and returns its content
+func buildModule(root string, g *types.GoType) string {
+	mb := NewModuleBuilder(root, "com.test")
+	_, err := mb.BuildModule(g)
+	So(err, ShouldBeNil)
+	So(mb.Close(), ShouldBeNil)
+	b, err := ioutil.ReadFile(mb.Output().Path)
+	So(err, ShouldBeNil)
+	return string(b)
+}
+
+func TestBuildModuleThreads(t *testing.T) {
+	Convey("Given a function run on each thread", t, func() {
+		thread := func(name string, thread string) types.GoFunction {
+			return types.GoFunction{Name: name, Params: []types.GoParams{types.GoParams{Name: "id", T: "string"}},
+				Directives: types.GoDirectives{Thread: thread}}
+		}
+		g := &types.GoType{PackageName: "hello", Functions: []types.GoFunction{
+			thread("Inline", ""),
+			thread("Background", types.ThreadBackground),
+			thread("Pooled", types.ThreadPool),
+		}, Returns: []types.GoParams{types.GoParams{}, types.GoParams{}, types.GoParams{}}}
+		Convey("When the module is built", func() {
+			content := buildModule(t.TempDir(), g)
+			Convey("Then the inline call runs on the calling thread", func() {
+				So(content, ShouldContainSubstring, "public void inline(String id, Promise promise) {\n"+
+					"\t\ttry {\n"+
+					"\t\t\tHello.inline(id);")
+			})
+			Convey("And the background call runs on the module's executor", func() {
+				So(content, ShouldContainSubstring, "public void background(String id, Promise promise) {\n"+
+					"\t\texecutor.execute(() -> {\n"+
+					"\t\t\ttry {\n"+
+					"\t\t\t\tHello.background(id);")
+				So(content, ShouldContainSubstring, "executor.shutdown();")
+			})
+			Convey("And the pooled call runs on the shared pool", func() {
+				So(content, ShouldContainSubstring, "public void pooled(String id, Promise promise) {\n"+
+					"\t\tGoExecutors.POOL.execute(() -> {\n"+
+					"\t\t\ttry {\n"+
+					"\t\t\t\tHello.pooled(id);")
+			})
+			Convey("And every promise is settled within its thread", func() {
+				So(content, ShouldContainSubstring, "\t\t\t\tpromise.resolve(null);\n"+
+					"\t\t\t} catch(Exception e) {\n"+
+					"\t\t\t\trejectGoError(promise, e);\n"+
+					"\t\t\t}\n"+
+					"\t\t});")
+			})
+		})
+	})
+}
+
+func TestBuildModuleNumberEnums(t *testing.T) {
+	Convey("Given functions passing an enum bridged as numbers", t, func() {
+		level := types.GoEnum{Name: "Level", Underlying: "int", Representation: types.EnumNumber, Values: []types.GoConstant{
+			types.GoConstant{Name: "LevelDebug", T: "Level", Value: "0"},
+			types.GoConstant{Name: "LevelInfo", T: "Level", Value: "1"},
+		}}
+		g := &types.GoType{PackageName: "hello", Enums: []types.GoEnum{level}, Functions: []types.GoFunction{
+			types.GoFunction{Name: "SetLevel", Params: []types.GoParams{types.GoParams{Name: "l", T: "Level"}}},
+			types.GoFunction{Name: "CurrentLevel", Results: []types.GoParams{types.GoParams{T: "Level"}}},
+			types.GoFunction{Name: "DefaultLevel", Results: []types.GoParams{types.GoParams{T: "Level"}},
+				Directives: types.GoDirectives{Sync: true}},
+		}, Returns: []types.GoParams{types.GoParams{}, types.GoParams{T: "Level"}, types.GoParams{T: "Level"}}}
+		Convey("When the module is built", func() {
+			content := buildModule(t.TempDir(), g)
+			Convey("Then parameters are taken as a double", func() {
+				So(content, ShouldContainSubstring, "public void setlevel(double l, Promise promise) {")
+			})
+			Convey("And converted to the Go integer, rejecting fractions and unknown values", func() {
+				So(content, ShouldContainSubstring, "final long lValue = (long) l;\n"+
+					"\t\tif (lValue != l || levelToString(lValue) == null) {")
+				So(content, ShouldContainSubstring, "Hello.setLevel(lValue);")
+			})
+			Convey("And results are resolved as a double", func() {
+				So(content, ShouldContainSubstring, "promise.resolve((double) returnValue1);")
+			})
+			Convey("And sync results are returned as a double", func() {
+				So(content, ShouldContainSubstring, "public double defaultlevel() {")
+				So(content, ShouldContainSubstring, "return (double) returnValue1;")
+			})
+		})
+	})
+}
+
+func TestErrorCondition(t *testing.T) {
+	Convey("Given errors known to the bridge", t, func() {
+		Convey("Then sentinel errors match the message, or the message wrapped", func() {
+			So(errorCondition(types.GoError{Message: "not found"}), ShouldEqual,
+				`message.equals("not found") || message.endsWith(": not found")`)
+		})
+		Convey("And typed errors match the start of the message", func() {
+			So(errorCondition(types.GoError{Message: "quota \"x\"\n", Prefix: true}), ShouldEqual,
+				`message.startsWith("quota \"x\"\n")`)
+		})
+	})
+}
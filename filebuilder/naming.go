@@ -0,0 +1,246 @@
+package filebuilder
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/steve-winter/reactgonative/types"
+)
+
+//moduleClassName is the name of the native module generated for packageName,
+//and the name it is registered with in NativeModules
+func moduleClassName(packageName string) string {
+	return strings.Title(strings.ToLower(packageName)) + "Module"
+}
+
+//javaKeywords are the names gomobile suffixes with an underscore
+var javaKeywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true,
+	"case": true, "catch": true, "char": true, "class": true, "const": true,
+	"continue": true, "default": true, "do": true, "double": true, "else": true,
+	"enum": true, "extends": true, "false": true, "final": true, "finally": true,
+	"float": true, "for": true, "goto": true, "if": true, "implements": true,
+	"import": true, "instanceof": true, "int": true, "interface": true, "long": true,
+	"native": true, "new": true, "null": true, "package": true, "private": true,
+	"protected": true, "public": true, "return": true, "short": true, "static": true,
+	"strictfp": true, "super": true, "switch": true, "synchronized": true, "this": true,
+	"throw": true, "throws": true, "transient": true, "true": true, "try": true,
+	"void": true, "volatile": true, "while": true,
+}
+
+//javaName returns name as gomobile writes it in Java, with an underscore
+//appended to keywords
+func javaName(name string) string {
+	if javaKeywords[name] {
+		return name + "_"
+	}
+	return name
+}
+
+//goJavaPackage is the Java package gomobile binds the Go package
+//packageName to, under the -javapkg prefix javaPkg when set. gomobile
+//uses the name in the package clause, never the import path, so nested
+//import paths and packages named differently to their directory bind
+//under their package name alone.
+func goJavaPackage(javaPkg string, packageName string) string {
+	if javaPkg == "" {
+		return javaName(packageName)
+	}
+	return javaPkg + "." + javaName(packageName)
+}
+
+//goSimpleClassName is the unqualified name of the class gomobile generates
+//for the functions and constants of packageName
+func goSimpleClassName(packageName string) string {
+	return javaName(strings.Title(packageName))
+}
+
+//goClassName is the class gomobile generates for the functions and
+//constants of packageName
+func goClassName(javaPkg string, packageName string) string {
+	return goJavaPackage(javaPkg, packageName) + "." + goSimpleClassName(packageName)
+}
+
+//goInterfaceName is the interface gomobile generates for iface
+func goInterfaceName(javaPkg string, packageName string, iface string) string {
+	return goJavaPackage(javaPkg, packageName) + "." + iface
+}
+
+//goMethodName is the static method gomobile generates for the function f
+func goMethodName(f *types.GoFunction) string {
+	return javaMethodName(f.Name)
+}
+
+//javaMethodName is the Java method gomobile generates for the Go function
+//or method name, so New becomes new_
+func javaMethodName(name string) string {
+	return javaName(gomobileMethodName(name))
+}
+
+//emitterClassName is the name of the class implementing the callback iface
+func emitterClassName(iface string) string {
+	return iface + "Emitter"
+}
+
+//eventName is the event emitted to JS when Go calls method on iface
+func eventName(packageName string, iface string, method string) string {
+	return moduleClassName(packageName) + "." + iface + "." + method
+}
+
+//reservedParams are names a parameter cannot take in the generated code:
+//JS reserved words that are not Java keywords, and the locals and fields
+//the generated methods declare alongside their parameters
+var reservedParams = map[string]bool{
+	"arguments": true, "await": true, "debugger": true, "delete": true, "eval": true,
+	"export": true, "function": true, "in": true, "let": true, "typeof": true,
+	"var": true, "with": true, "yield": true,
+	"constants": true, "e": true, "event": true, "executor": true, "message": true,
+	"promise": true, "reactContext": true, "returnParam1": true, "returnValue1": true,
+	"userInfo": true,
+}
+
+//paramName returns the name of p, or a positional name when p is unnamed.
+//Keywords and reserved names are suffixed with an underscore.
+func paramName(p types.GoParams, i int) string {
+	if p.Name == "" || p.Name == "_" {
+		return "arg" + strconv.Itoa(i)
+	}
+	if javaKeywords[p.Name] || reservedParams[p.Name] {
+		return p.Name + "_"
+	}
+	return p.Name
+}
+
+//jsParams returns the parameters of f passed from JS, which excludes the
+//callbacks the bridge supplies itself
+func jsParams(g *types.GoType, f *types.GoFunction) []types.GoParams {
+	params := make([]types.GoParams, 0, len(f.Params))
+	for i, p := range f.Params {
+		if g.IsCallback(p.T) {
+			continue
+		}
+		params = append(params, types.GoParams{Name: paramName(p, i), T: p.T})
+	}
+	return params
+}
+
+//bridgeParams returns params with each enum, and each integer React Native
+//has no type for, replaced by the type the bridge passes it from JS as
+func bridgeParams(g *types.GoType, params []types.GoParams) []types.GoParams {
+	bridged := make([]types.GoParams, 0, len(params))
+	for _, p := range params {
+		t := g.BridgeType(p.T)
+		if widened(t) {
+			t = "float64"
+		}
+		bridged = append(bridged, types.GoParams{Name: p.Name, T: t})
+	}
+	return bridged
+}
+
+//reactType is the Java type the bridge passes the Go type t to and from JS
+//as. React Native has no long, short or byte, so those integers pass as
+//double.
+func reactType(t string) string {
+	switch t {
+	case "int", "int8", "int16", "int64":
+		return "double"
+	}
+	return types.GoToJava(t)
+}
+
+//widened identifies whether the Go type t passes to and from JS as a
+//reactType other than its Java type, so must be cast to and from it
+func widened(t string) bool {
+	return reactType(t) != types.GoToJava(t)
+}
+
+//javaString quotes s as a Java string literal. Line breaks must not be
+//written as unicode escapes, which Java translates before parsing.
+func javaString(s string) string {
+	var b strings.Builder
+	b.WriteString("\"")
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString("\\n")
+		case r == '\r':
+			b.WriteString("\\r")
+		case r == '\t':
+			b.WriteString("\\t")
+		case r < ' ' || r > '~':
+			for _, u := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, "\\u%04x", u)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteString("\"")
+	return b.String()
+}
+
+//enumConverterName is the module's helper converting the enum to or from
+//its JS value, such as statusFromString
+func enumConverterName(enum string, direction string) string {
+	return gomobileMethodName(enum) + direction
+}
+
+//enumConverter is the helper converting the enum t in direction, qualified
+//by the module of the package declaring t when that is another package
+func enumConverter(g *types.GoType, t string, direction string) string {
+	owner, name := g.Resolve(t)
+	if owner == nil || owner == g {
+		return enumConverterName(name, direction)
+	}
+	return moduleClassName(owner.PackageName) + "." + enumConverterName(name, direction)
+}
+
+//gomobileMethodName is the Java name gomobile gives the Go method name,
+//lower casing the leading upper case run, so OnHTTPDone becomes onHTTPDone
+//and URL becomes url
+func gomobileMethodName(name string) string {
+	conv := make([]rune, 0, len(name))
+	s := name
+	for len(s) > 0 {
+		r, n := utf8.DecodeRuneInString(s)
+		if !unicode.IsUpper(r) {
+			if l := len(conv); l > 1 {
+				conv[l-1] = unicode.ToUpper(conv[l-1])
+			}
+			return string(conv) + s
+		}
+		conv = append(conv, unicode.ToLower(r))
+		s = s[n:]
+	}
+	return string(conv)
+}
+
+//bridgeRootPackage is the Java package holding the classes shared by every
+//bridged Go package
+func bridgeRootPackage(root string) string {
+	if root == "" {
+		return "bridge"
+	}
+	return root + ".bridge"
+}
+
+//bridgePackageName is the Java package the bridge classes for the Go
+//package name are generated in
+func bridgePackageName(name string, root string) string {
+	return bridgeRootPackage(root) + "." + name
+}
+
+//javaFileName is the path of className within the Java package javaPackage,
+//under the source root
+func javaFileName(root string, javaPackage string, className string) string {
+	return filepath.Join(root, strings.Replace(javaPackage, ".", "/", -1), className+".java")
+}
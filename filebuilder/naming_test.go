@@ -0,0 +1,92 @@
+package filebuilder
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+func TestGomobileMethodName(t *testing.T) {
+	Convey("Given exported Go method names", t, func() {
+		Convey("Then the leading upper case run is lowered as gomobile does", func() {
+			So(gomobileMethodName("OnProgress"), ShouldEqual, "onProgress")
+			So(gomobileMethodName("URL"), ShouldEqual, "url")
+			So(gomobileMethodName("HTTPServer"), ShouldEqual, "httpServer")
+			So(gomobileMethodName("Done"), ShouldEqual, "done")
+		})
+		Convey("And Java keywords are suffixed with an underscore", func() {
+			So(javaMethodName("New"), ShouldEqual, "new_")
+			So(javaMethodName("Newer"), ShouldEqual, "newer")
+		})
+	})
+}
+
+func TestParamName(t *testing.T) {
+	Convey("Given Go parameter names", t, func() {
+		Convey("Then unnamed parameters are named by position", func() {
+			So(paramName(types.GoParams{Name: "_"}, 2), ShouldEqual, "arg2")
+			So(paramName(types.GoParams{}, 0), ShouldEqual, "arg0")
+		})
+		Convey("And keywords and names used by the bridge are suffixed with an underscore", func() {
+			So(paramName(types.GoParams{Name: "class"}, 0), ShouldEqual, "class_")
+			So(paramName(types.GoParams{Name: "function"}, 0), ShouldEqual, "function_")
+			So(paramName(types.GoParams{Name: "promise"}, 0), ShouldEqual, "promise_")
+			So(paramName(types.GoParams{Name: "name"}, 0), ShouldEqual, "name")
+		})
+	})
+}
+
+func TestGoClassName(t *testing.T) {
+	Convey("Given a Go package bound without a javapkg", t, func() {
+		Convey("Then the Java package is the Go package name", func() {
+			So(goClassName("", "hello"), ShouldEqual, "hello.Hello")
+			So(goInterfaceName("", "hello", "Counter"), ShouldEqual, "hello.Counter")
+		})
+		Convey("And a keyword package name is suffixed with an underscore", func() {
+			So(goClassName("", "native"), ShouldEqual, "native_.Native")
+		})
+	})
+	Convey("Given a Go package bound with a javapkg", t, func() {
+		Convey("Then the Java package is under the javapkg", func() {
+			So(goClassName("com.example.go", "hello"), ShouldEqual, "com.example.go.hello.Hello")
+			So(goInterfaceName("com.example.go", "hello", "Counter"), ShouldEqual, "com.example.go.hello.Counter")
+		})
+	})
+	Convey("Given a Go package with upper case in its name", t, func() {
+		Convey("Then the case is kept", func() {
+			So(goClassName("", "myPkg"), ShouldEqual, "myPkg.MyPkg")
+		})
+	})
+}
+
+func TestJavaString(t *testing.T) {
+	Convey("Given strings to quote as Java literals", t, func() {
+		Convey("Then quotes and line breaks are escaped", func() {
+			So(javaString("a \"b\"\n"), ShouldEqual, `"a \"b\"\n"`)
+		})
+		Convey("And other characters are written as unicode escapes", func() {
+			So(javaString("é\x00"), ShouldEqual, `"\u00e9\u0000"`)
+		})
+	})
+}
+
+func TestReactType(t *testing.T) {
+	Convey("Given Go types passed to and from JS", t, func() {
+		Convey("Then integers React Native has no type for pass as a double", func() {
+			So(reactType("int"), ShouldEqual, "double")
+			So(reactType("int64"), ShouldEqual, "double")
+			So(widened("int16"), ShouldBeTrue)
+		})
+		Convey("And the others pass as their Java type", func() {
+			So(reactType("int32"), ShouldEqual, "int")
+			So(reactType("string"), ShouldEqual, "String")
+			So(widened("float64"), ShouldBeFalse)
+		})
+		Convey("And parameters are declared with the widened type", func() {
+			g := &types.GoType{PackageName: "hello"}
+			params := bridgeParams(g, []types.GoParams{types.GoParams{Name: "n", T: "int"}, types.GoParams{Name: "s", T: "string"}})
+			So(params, ShouldResemble, []types.GoParams{types.GoParams{Name: "n", T: "float64"}, types.GoParams{Name: "s", T: "string"}})
+		})
+	})
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/steve-winter/reactgonative/types"
 )
 
+//aggregateClassName is the package registering the modules of every Go package
+const aggregateClassName = "GoBridgePackage"
+
 // PackageBuilder is the creator of each Packages boilerplate
 type PackageBuilder struct {
 	javaFile *JavaFile
@@ -45,12 +48,41 @@ func (pb *PackageBuilder) buildFileName(pkgName string, pkgRoot string) string {
 // An error is returned if any write fail
 func (pb *PackageBuilder) BuildPackage(packageName string) error {
 	fileName := pb.buildFileName(packageName, pb.javaFile.packageRoot)
+	return pb.build(fileName, pb.createPackageName(packageName, pb.javaFile.packageRoot),
+		pb.className(packageName), nil, []string{pb.moduleName(packageName)})
+}
+
+// BuildAggregatePackage generates a single package registering the modules
+// of every package in packageNames, so the app registers one package however
+// many Go packages are bridged.
+// An error is returned if any write fail
+func (pb *PackageBuilder) BuildAggregatePackage(packageNames []string) error {
+	javaPackage := bridgeRootPackage(pb.javaFile.packageRoot)
+	imports := make([]string, 0, len(packageNames))
+	modules := make([]string, 0, len(packageNames))
+	for _, name := range packageNames {
+		imports = append(imports, bridgePackageName(name, pb.javaFile.packageRoot)+"."+pb.moduleName(name))
+		modules = append(modules, pb.moduleName(name))
+	}
+	fileName := javaFileName(pb.javaFile.fileName, javaPackage, aggregateClassName)
+	return pb.build(fileName, javaPackage, aggregateClassName, imports, modules)
+}
+
+// AggregateClassName is the fully qualified Java class BuildAggregatePackage generates
+func (pb *PackageBuilder) AggregateClassName() string {
+	return bridgeRootPackage(pb.javaFile.packageRoot) + "." + aggregateClassName
+}
+
+//build writes the ReactPackage className in javaPackage, creating modules.
+//imports holds the modules' classes when they are in another Java package.
+func (pb *PackageBuilder) build(fileName string, javaPackage string, className string,
+	imports []string, modules []string) error {
 	pb.javaFile.setFileName(fileName)
 	err := pb.create()
 	if err != nil {
 		return err
 	}
-	err = pb.javaFile.writePackageLine(pb.createPackageName(packageName, pb.javaFile.packageRoot))
+	err = pb.javaFile.writePackageLine(javaPackage)
 	if err != nil {
 		return err
 	}
@@ -62,11 +94,17 @@ func (pb *PackageBuilder) BuildPackage(packageName string) error {
 	if err != nil {
 		return err
 	}
+	for _, val := range imports {
+		err = pb.javaFile.writeImport(val)
+		if err != nil {
+			return err
+		}
+	}
 	err = pb.javaFile.writeBlank(1)
 	if err != nil {
 		return err
 	}
-	err = pb.javaFile.writeClassHeader(pb.className(packageName),
+	err = pb.javaFile.writeClassHeader(className,
 		"", "ReactPackage")
 	if err != nil {
 		return err
@@ -75,7 +113,7 @@ func (pb *PackageBuilder) BuildPackage(packageName string) error {
 	if err != nil {
 		return err
 	}
-	err = pb.buildNativeModulesMethod(packageName)
+	err = pb.buildNativeModulesMethod(modules)
 	if err != nil {
 		return err
 	}
@@ -129,7 +167,7 @@ func (pb *PackageBuilder) buildImports() error {
 	return nil
 }
 
-func (pb *PackageBuilder) buildNativeModulesMethod(packageName string) error {
+func (pb *PackageBuilder) buildNativeModulesMethod(modules []string) error {
 	params := make([]types.GoParams, 0)
 	params = append(params, types.GoParams{Name: context, T: "ReactApplicationContext"})
 	err := pb.javaFile.writeAnnotation("Override")
@@ -144,9 +182,11 @@ func (pb *PackageBuilder) buildNativeModulesMethod(packageName string) error {
 	if err != nil {
 		return err
 	}
-	err = pb.javaFile.writeMethodBody("modules.add(new " + pb.moduleName(packageName) + "(" + context + "))")
-	if err != nil {
-		return err
+	for _, module := range modules {
+		err = pb.javaFile.writeMethodBody("modules.add(new " + module + "(" + context + "))")
+		if err != nil {
+			return err
+		}
 	}
 	err = pb.javaFile.writeReturnDynamic("modules")
 	if err != nil {
@@ -206,10 +246,21 @@ func (pb *PackageBuilder) Close() error {
 	return pb.javaFile.close()
 }
 
+// Output is the file built, once closed
+func (pb *PackageBuilder) Output() Output {
+	return pb.javaFile.output()
+}
+
 func (pb *PackageBuilder) create() error {
 	return pb.javaFile.createFile()
 }
 
+// QualifiedClassName is the fully qualified Java class BuildPackage
+// generates for packageName
+func (pb *PackageBuilder) QualifiedClassName(packageName string) string {
+	return pb.createPackageName(packageName, pb.javaFile.packageRoot) + "." + pb.className(packageName)
+}
+
 func (pb *PackageBuilder) className(packageName string) string {
 	return pb.importedPackageName(packageName) + "Package"
 }
@@ -0,0 +1,32 @@
+package filebuilder
+
+import (
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildAggregatePackage(t *testing.T) {
+	Convey("Given two bridged Go packages", t, func() {
+		Convey("When the aggregate package is built", func() {
+			pb := NewPackageBuilder(t.TempDir(), "com.test")
+			err := pb.BuildAggregatePackage([]string{"hello", "world"})
+			So(err, ShouldBeNil)
+			So(pb.Close(), ShouldBeNil)
+			b, err := ioutil.ReadFile(pb.Output().Path)
+			So(err, ShouldBeNil)
+			content := string(b)
+			Convey("Then it is in the bridge root package", func() {
+				So(pb.AggregateClassName(), ShouldEqual, "com.test.bridge.GoBridgePackage")
+				So(content, ShouldStartWith, "package com.test.bridge;")
+			})
+			Convey("And it imports and creates every module", func() {
+				So(content, ShouldContainSubstring, "import com.test.bridge.hello.HelloModule;")
+				So(content, ShouldContainSubstring, "import com.test.bridge.world.WorldModule;")
+				So(content, ShouldContainSubstring, "modules.add(new HelloModule(reactContext));")
+				So(content, ShouldContainSubstring, "modules.add(new WorldModule(reactContext));")
+			})
+		})
+	})
+}
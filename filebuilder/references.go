@@ -0,0 +1,46 @@
+package filebuilder
+
+import (
+	"go/token"
+
+	"github.com/steve-winter/reactgonative/types"
+)
+
+// Reference is a class, or a member of a class, of the Java API gomobile
+// generates that the bridge for a package uses. Member is blank for the class itself.
+type Reference struct {
+	Class  string
+	Member string
+	Pos    token.Position
+}
+
+func (r Reference) String() string {
+	if r.Member == "" {
+		return r.Class
+	}
+	return r.Class + "." + r.Member
+}
+
+// References returns every class, method and field of gomobile's Java API
+// the generated bridge for g uses
+func References(g *types.GoType) []Reference {
+	class := goClassName(g.JavaPkg, g.PackageName)
+	refs := []Reference{Reference{Class: class}}
+	for i, f := range g.Functions {
+		if g.IsIgnored(i) {
+			continue
+		}
+		refs = append(refs, Reference{Class: class, Member: goMethodName(&f), Pos: f.Pos})
+	}
+	for _, c := range g.Constants {
+		refs = append(refs, Reference{Class: class, Member: c.Name, Pos: c.Pos})
+	}
+	for _, c := range g.Callbacks() {
+		iface := goInterfaceName(g.JavaPkg, g.PackageName, c.Name)
+		refs = append(refs, Reference{Class: iface, Pos: c.Pos})
+		for _, m := range c.Methods {
+			refs = append(refs, Reference{Class: iface, Member: javaMethodName(m.Name), Pos: m.Pos})
+		}
+	}
+	return refs
+}
@@ -0,0 +1,86 @@
+package filebuilder
+
+import (
+	"go/token"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/binder"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+//referenceNames returns the name of each reference, in order
+func referenceNames(refs []Reference) []string {
+	names := make([]string, 0, len(refs))
+	for _, r := range refs {
+		names = append(names, r.String())
+	}
+	return names
+}
+
+func TestReferences(t *testing.T) {
+	Convey("Given a package with functions, a constant and a callback", t, func() {
+		counter := types.GoTypeSpec{Name: "Counter", Kind: types.KindInterface, Pos: token.Position{Line: 3},
+			Methods: []types.GoFunction{types.GoFunction{Name: "OnProgress", Pos: token.Position{Line: 4}}}}
+		g := &types.GoType{
+			PackageName: "hello",
+			Types:       map[string]types.GoTypeSpec{"Counter": counter},
+			Functions: []types.GoFunction{
+				types.GoFunction{Name: "Greetings", Pos: token.Position{Line: 7}},
+				types.GoFunction{Name: "New"},
+				types.GoFunction{Name: "HTTPGet"},
+				types.GoFunction{Name: "Hidden", Directives: types.GoDirectives{Ignore: true}},
+				types.GoFunction{Name: "Watch", Params: []types.GoParams{types.GoParams{Name: "c", T: "Counter"}}},
+			},
+			Constants: []types.GoConstant{types.GoConstant{Name: "MaxItems", Pos: token.Position{Line: 9}}},
+		}
+		Convey("When its references are listed", func() {
+			refs := References(g)
+			Convey("Then the package class and each bridged method are referenced", func() {
+				So(referenceNames(refs)[:5], ShouldResemble, []string{
+					"hello.Hello",
+					"hello.Hello.greetings",
+					"hello.Hello.new_",
+					"hello.Hello.httpGet",
+					"hello.Hello.watch",
+				})
+				So(refs[1].Pos.Line, ShouldEqual, 7)
+			})
+			Convey("And constants are referenced as fields", func() {
+				So(refs[5].String(), ShouldEqual, "hello.Hello.MaxItems")
+				So(refs[5].Pos.Line, ShouldEqual, 9)
+			})
+			Convey("And callbacks are referenced as interfaces with their methods", func() {
+				So(referenceNames(refs)[6:], ShouldResemble, []string{"hello.Counter", "hello.Counter.onProgress"})
+				So(refs[6].Pos.Line, ShouldEqual, 3)
+				So(refs[7].Pos.Line, ShouldEqual, 4)
+			})
+		})
+		Convey("When it is bound with a javapkg", func() {
+			g.JavaPkg = "com.example.go"
+			refs := References(g)
+			Convey("Then the classes are under the javapkg", func() {
+				So(refs[0].String(), ShouldEqual, "com.example.go.hello.Hello")
+				So(refs[6].String(), ShouldEqual, "com.example.go.hello.Counter")
+			})
+		})
+		Convey("When they are checked against an API missing symbols", func() {
+			api := binder.API{
+				"hello.Hello": &binder.Class{Name: "hello.Hello",
+					Fields:  map[string]bool{"MaxItems": true},
+					Methods: map[string]bool{"greetings": true, "httpGet": true, "watch": true}},
+				"hello.Counter": &binder.Class{Name: "hello.Counter",
+					Methods: map[string]bool{"onProgress": true}},
+			}
+			missing := make([]string, 0)
+			for _, r := range References(g) {
+				if !api.Has(r.Class, r.Member) {
+					missing = append(missing, r.String())
+				}
+			}
+			Convey("Then only the missing symbols are found", func() {
+				So(missing, ShouldResemble, []string{"hello.Hello.new_"})
+			})
+		})
+	})
+}
@@ -0,0 +1,225 @@
+package filebuilder
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/steve-winter/reactgonative/types"
+)
+
+// ScriptBuilder is the creator of the JS wrapper for each native module,
+// exposing its methods and helpers to subscribe to callback events
+type ScriptBuilder struct {
+	javaFile *JavaFile
+}
+
+// NewScriptBuilder returns a new ScriptBuilder writing under the JS root.
+// The file is not opened or created at this point.
+func NewScriptBuilder(root string) ScriptBuilder {
+	return ScriptBuilder{
+		javaFile: NewJavaFile(root, ""),
+	}
+}
+
+//scriptName is the name of the JS module for packageName, without extension
+func scriptName(packageName string) string {
+	return strings.ToLower(packageName)
+}
+
+//scriptFileName is the path of the JS file for packageName under root,
+//with the given extension
+func scriptFileName(root string, packageName string, ext string) string {
+	return filepath.Join(root, scriptName(packageName)+ext)
+}
+
+//BuildScript generates the JS wrapper for the functions in g.
+//Returns the file name created, or an error if a write fails
+func (sb *ScriptBuilder) BuildScript(g *types.GoType) (string, error) {
+	fileName := scriptFileName(sb.javaFile.fileName, g.PackageName, ".js")
+	sb.javaFile.setFileName(fileName)
+	err := sb.javaFile.createFile()
+	if err != nil {
+		return "", err
+	}
+	module := moduleClassName(g.PackageName)
+	callbacks := g.Callbacks()
+	if len(callbacks) > 0 {
+		err = sb.javaFile.writeImport("{ NativeModules, NativeEventEmitter } from 'react-native'")
+	} else {
+		err = sb.javaFile.writeImport("{ NativeModules } from 'react-native'")
+	}
+	if err != nil {
+		return "", err
+	}
+	err = sb.javaFile.writeBlank(1)
+	if err != nil {
+		return "", err
+	}
+	err = sb.javaFile.writeMethodBody("const " + module + " = NativeModules." + module)
+	if err != nil {
+		return "", err
+	}
+	if len(callbacks) > 0 {
+		err = sb.javaFile.writeMethodBody("const emitter = new NativeEventEmitter(" + module + ")")
+		if err != nil {
+			return "", err
+		}
+	}
+	err = sb.javaFile.writeBlank(1)
+	if err != nil {
+		return "", err
+	}
+	if len(g.Constants) > 0 {
+		err = sb.buildConstants(g, module)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, e := range g.Enums {
+		err = sb.buildEnum(&e)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, c := range callbacks {
+		err = sb.buildEvents(g.PackageName, c)
+		if err != nil {
+			return "", err
+		}
+	}
+	for i, f := range g.Functions {
+		if g.IsIgnored(i) {
+			continue
+		}
+		err = sb.buildFunction(g, module, &f)
+		if err != nil {
+			return "", err
+		}
+	}
+	err = sb.javaFile.writeMethodBody("export default " + module)
+	if err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
+
+//buildConstants exports each constant read from the module's getConstants
+func (sb *ScriptBuilder) buildConstants(g *types.GoType, module string) error {
+	for _, c := range g.Constants {
+		err := sb.javaFile.writeMethodBody("export const " + c.Name + " = " + module + "." + c.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return sb.javaFile.writeBlank(1)
+}
+
+//buildEnum exports the values of e, as passed to and from the module
+func (sb *ScriptBuilder) buildEnum(e *types.GoEnum) error {
+	err := sb.javaFile.writeLine("export const " + e.Name + " = Object.freeze({")
+	if err != nil {
+		return err
+	}
+	for _, v := range e.Values {
+		err = sb.javaFile.writeLineN(e.JSValue(v) + ": " + enumLiteral(e, v) + ",")
+		if err != nil {
+			return err
+		}
+	}
+	err = sb.javaFile.writeLineN("});")
+	if err != nil {
+		return err
+	}
+	return sb.javaFile.writeBlank(1)
+}
+
+//enumLiteral is the JS literal of the value v of e
+func enumLiteral(e *types.GoEnum, v types.GoConstant) string {
+	if e.IsString() {
+		return "'" + e.JSValue(v) + "'"
+	}
+	return v.Value
+}
+
+//buildEvents writes the event names of the callback c, and a function to
+//subscribe to each
+func (sb *ScriptBuilder) buildEvents(packageName string, c types.GoTypeSpec) error {
+	err := sb.javaFile.writeLine("export const " + c.Name + "Events = {")
+	if err != nil {
+		return err
+	}
+	for _, m := range c.Methods {
+		err = sb.javaFile.writeLineN(m.Name + ": '" + eventName(packageName, c.Name, m.Name) + "',")
+		if err != nil {
+			return err
+		}
+	}
+	err = sb.javaFile.writeLineN("};")
+	if err != nil {
+		return err
+	}
+	err = sb.javaFile.writeBlank(1)
+	if err != nil {
+		return err
+	}
+	for _, m := range c.Methods {
+		err = sb.javaFile.writeLine("export function " + listenerFunctionName(c.Name, m.Name) + "(listener) {")
+		if err != nil {
+			return err
+		}
+		err = sb.javaFile.writeMethodBody("return emitter.addListener(" + c.Name + "Events." + m.Name + ", listener)")
+		if err != nil {
+			return err
+		}
+		err = sb.javaFile.writeCloseTag()
+		if err != nil {
+			return err
+		}
+		err = sb.javaFile.writeBlank(1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sb *ScriptBuilder) buildFunction(g *types.GoType, module string, f *types.GoFunction) error {
+	names := paramNames(jsParams(g, f))
+	err := sb.javaFile.writeLine("export function " + f.JSName() + "(" + names + ") {")
+	if err != nil {
+		return err
+	}
+	err = sb.javaFile.writeMethodBody("return " + module + "." + f.JSName() + "(" + names + ")")
+	if err != nil {
+		return err
+	}
+	err = sb.javaFile.writeCloseTag()
+	if err != nil {
+		return err
+	}
+	return sb.javaFile.writeBlank(1)
+}
+
+// Close will close the internal javaFile
+func (sb *ScriptBuilder) Close() error {
+	return sb.javaFile.close()
+}
+
+// Output is the file built, once closed
+func (sb *ScriptBuilder) Output() Output {
+	return sb.javaFile.output()
+}
+
+//listenerFunctionName is the JS function subscribing to method of callback
+func listenerFunctionName(callback string, method string) string {
+	return "add" + callback + method + "Listener"
+}
+
+//paramNames joins the names of params for a JS parameter or argument list
+func paramNames(params []types.GoParams) string {
+	names := make([]string, 0, len(params))
+	for _, p := range params {
+		names = append(names, p.Name)
+	}
+	return strings.Join(names, ", ")
+}
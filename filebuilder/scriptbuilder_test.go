@@ -0,0 +1,23 @@
+package filebuilder
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+func TestEnumLiteral(t *testing.T) {
+	Convey("Given an enum", t, func() {
+		e := types.GoEnum{Name: "Status", Underlying: "int", Values: []types.GoConstant{
+			types.GoConstant{Name: "StatusActive", T: "Status", Value: "0"},
+		}}
+		Convey("Then string enums use the trimmed constant name", func() {
+			So(enumLiteral(&e, e.Values[0]), ShouldEqual, "'Active'")
+		})
+		Convey("And number enums use the Go value", func() {
+			e.Representation = types.EnumNumber
+			So(enumLiteral(&e, e.Values[0]), ShouldEqual, "0")
+		})
+	})
+}
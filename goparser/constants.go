@@ -0,0 +1,216 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	gotypes "go/types"
+
+	"github.com/steve-winter/reactgonative/diagnostics"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+//parseConstants records the exported constants of a const declaration.
+//Values are evaluated so untyped constants can be given their default type.
+//known holds the constants already evaluated in the file, so later
+//declarations can refer to them.
+func parseConstants(fset *token.FileSet, diags *diagnostics.List, x *ast.GenDecl, m *types.GoType, known map[string]constant.Value) {
+	if x.Tok != token.CONST {
+		return
+	}
+	var lastType ast.Expr
+	var lastValues []ast.Expr
+	for iota, spec := range x.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		if valueSpec.Type != nil || len(valueSpec.Values) > 0 {
+			lastType, lastValues = valueSpec.Type, valueSpec.Values
+		}
+		doc := valueSpec.Doc
+		if doc == nil && len(x.Specs) == 1 {
+			doc = x.Doc
+		}
+		for i, name := range valueSpec.Names {
+			if i >= len(lastValues) {
+				break
+			}
+			v, ok := evalConst(lastValues[i], int64(iota), known)
+			if ok {
+				known[name.Name] = v
+			}
+			if !name.IsExported() {
+				continue
+			}
+			pos := position(fset, name.Pos())
+			if !ok {
+				diags.Warnf(pos, diagnostics.CodeNotBridged, "%s: constant value could not be determined", name.Name)
+				continue
+			}
+			c := types.GoConstant{
+				Name:       name.Name,
+				T:          constType(lastType, lastValues[i], v),
+				Value:      v.ExactString(),
+				Directives: parseDirectives(fset, diags, doc),
+				Pos:        pos,
+			}
+			m.Constants = append(m.Constants, c)
+		}
+	}
+}
+
+//constType is the declared type of a constant, or the default type of its value
+func constType(declared ast.Expr, value ast.Expr, v constant.Value) string {
+	if declared != nil {
+		return gotypes.ExprString(declared)
+	}
+	if call, ok := value.(*ast.CallExpr); ok && len(call.Args) == 1 {
+		return gotypes.ExprString(call.Fun)
+	}
+	switch v.Kind() {
+	case constant.Bool:
+		return "bool"
+	case constant.String:
+		return "string"
+	case constant.Float:
+		return "float64"
+	}
+	if ident, ok := value.(*ast.BasicLit); ok && ident.Kind == token.CHAR {
+		return "rune"
+	}
+	return "int"
+}
+
+//evalConst evaluates the constant expression expr, returning false when it
+//uses anything other than literals, iota, conversions and earlier constants
+func evalConst(expr ast.Expr, iota int64, known map[string]constant.Value) (constant.Value, bool) {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		v := constant.MakeFromLiteral(x.Value, x.Kind, 0)
+		return v, v.Kind() != constant.Unknown
+	case *ast.Ident:
+		switch x.Name {
+		case "iota":
+			return constant.MakeInt64(iota), true
+		case "true", "false":
+			return constant.MakeBool(x.Name == "true"), true
+		}
+		v, ok := known[x.Name]
+		return v, ok
+	case *ast.ParenExpr:
+		return evalConst(x.X, iota, known)
+	case *ast.UnaryExpr:
+		v, ok := evalConst(x.X, iota, known)
+		if !ok || !unaryKind(x.Op, v.Kind()) {
+			return nil, false
+		}
+		return constant.UnaryOp(x.Op, v, 0), true
+	case *ast.CallExpr:
+		if len(x.Args) != 1 {
+			return nil, false
+		}
+		if ident, ok := x.Fun.(*ast.Ident); !ok || !types.IsBasicType(ident.Name) {
+			return nil, false
+		}
+		return evalConst(x.Args[0], iota, known)
+	case *ast.BinaryExpr:
+		return evalBinary(x, iota, known)
+	}
+	return nil, false
+}
+
+//maxShift is the largest shift count evaluated, well beyond any constant
+//gomobile can bind
+const maxShift = 1024
+
+//evalBinary evaluates x, returning false when its operands are of kinds the
+//operator does not apply to, as in source that does not type check, or when
+//it divides by zero
+func evalBinary(x *ast.BinaryExpr, iota int64, known map[string]constant.Value) (constant.Value, bool) {
+	left, ok := evalConst(x.X, iota, known)
+	if !ok {
+		return nil, false
+	}
+	right, ok := evalConst(x.Y, iota, known)
+	if !ok {
+		return nil, false
+	}
+	switch x.Op {
+	case token.SHL, token.SHR:
+		if left.Kind() != constant.Int || right.Kind() != constant.Int {
+			return nil, false
+		}
+		s, ok := constant.Uint64Val(right)
+		if !ok || s > maxShift {
+			return nil, false
+		}
+		return constant.Shift(left, x.Op, uint(s)), true
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		if !compareKinds(x.Op, left.Kind(), right.Kind()) {
+			return nil, false
+		}
+		return constant.MakeBool(constant.Compare(left, x.Op, right)), true
+	}
+	if !binaryKinds(x.Op, left.Kind(), right.Kind()) {
+		return nil, false
+	}
+	if (x.Op == token.QUO || x.Op == token.REM) && constant.Sign(right) == 0 {
+		return nil, false
+	}
+	if x.Op == token.QUO && left.Kind() == constant.Int && right.Kind() == constant.Int {
+		return constant.BinaryOp(left, token.QUO_ASSIGN, right), true
+	}
+	v := constant.BinaryOp(left, x.Op, right)
+	return v, v.Kind() != constant.Unknown
+}
+
+//isNumeric identifies whether k is the kind of a numeric constant
+func isNumeric(k constant.Kind) bool {
+	return k == constant.Int || k == constant.Float || k == constant.Complex
+}
+
+//unaryKind identifies whether the unary operator op applies to constants of
+//kind k
+func unaryKind(op token.Token, k constant.Kind) bool {
+	switch op {
+	case token.ADD, token.SUB:
+		return isNumeric(k)
+	case token.XOR:
+		return k == constant.Int
+	case token.NOT:
+		return k == constant.Bool
+	}
+	return false
+}
+
+//compareKinds identifies whether the comparison op applies to constants of
+//kinds left and right. Bools and complex numbers are only equal or not.
+func compareKinds(op token.Token, left constant.Kind, right constant.Kind) bool {
+	ordered := op != token.EQL && op != token.NEQ
+	switch {
+	case left == constant.String && right == constant.String:
+		return true
+	case left == constant.Bool && right == constant.Bool:
+		return !ordered
+	case isNumeric(left) && isNumeric(right):
+		return !ordered || (left != constant.Complex && right != constant.Complex)
+	}
+	return false
+}
+
+//binaryKinds identifies whether the arithmetic or logical operator op applies
+//to constants of kinds left and right
+func binaryKinds(op token.Token, left constant.Kind, right constant.Kind) bool {
+	switch op {
+	case token.ADD:
+		return (left == constant.String && right == constant.String) || (isNumeric(left) && isNumeric(right))
+	case token.SUB, token.MUL, token.QUO:
+		return isNumeric(left) && isNumeric(right)
+	case token.REM, token.AND, token.OR, token.XOR, token.AND_NOT:
+		return left == constant.Int && right == constant.Int
+	case token.LAND, token.LOR:
+		return left == constant.Bool && right == constant.Bool
+	}
+	return false
+}
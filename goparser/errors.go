@@ -0,0 +1,184 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/token"
+	gotypes "go/types"
+	"strconv"
+	"strings"
+
+	"github.com/steve-winter/reactgonative/diagnostics"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+//parseSentinelErrors records exported error variables created from a
+//constant message, such as var ErrNotFound = errors.New("not found")
+func parseSentinelErrors(fset *token.FileSet, diags *diagnostics.List, x *ast.GenDecl, m *types.GoType) {
+	if x.Tok != token.VAR {
+		return
+	}
+	for _, spec := range x.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		doc := valueSpec.Doc
+		if doc == nil && len(x.Specs) == 1 {
+			doc = x.Doc
+		}
+		for i, name := range valueSpec.Names {
+			if !name.IsExported() || i >= len(valueSpec.Values) {
+				continue
+			}
+			message, ok := sentinelMessage(valueSpec.Values[i])
+			if !ok {
+				continue
+			}
+			directives := parseDirectives(fset, diags, doc)
+			code := directives.Code
+			if code == "" {
+				code = types.ErrorCode(name.Name)
+			}
+			m.Errors = append(m.Errors, types.GoError{
+				Name:    name.Name,
+				Code:    code,
+				Message: message,
+				Pos:     position(fset, name.Pos()),
+			})
+		}
+	}
+}
+
+//sentinelMessage returns the message of errors.New or fmt.Errorf called with
+//a constant message and nothing to format
+func sentinelMessage(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+	fun := gotypes.ExprString(call.Fun)
+	if fun != "errors.New" && fun != "fmt.Errorf" {
+		return "", false
+	}
+	message, ok := stringLiteral(call.Args[0])
+	if !ok || (fun == "fmt.Errorf" && strings.Contains(message, "%")) {
+		return "", false
+	}
+	return message, true
+}
+
+//parseErrorMethod records the Code and Error methods of error types.
+//Each is recorded as a partial GoError, combined by groupErrors once the
+//whole package has been parsed.
+func parseErrorMethod(fset *token.FileSet, x *ast.FuncDecl, m *types.GoType) {
+	if x.Recv == nil || len(x.Recv.List) == 0 || x.Body == nil || len(x.Body.List) != 1 {
+		return
+	}
+	if x.Type.Params.NumFields() != 0 || x.Type.Results.NumFields() != 1 {
+		return
+	}
+	ret, ok := x.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return
+	}
+	e := types.GoError{
+		Name:  strings.TrimPrefix(gotypes.ExprString(x.Recv.List[0].Type), "*"),
+		Typed: true,
+		Pos:   position(fset, x.Name.Pos()),
+	}
+	switch x.Name.Name {
+	case "Code":
+		if e.Code, ok = stringLiteral(ret.Results[0]); !ok {
+			return
+		}
+	case "Error":
+		if e.Message, e.Prefix = messagePrefix(ret.Results[0]); e.Message == "" {
+			return
+		}
+	default:
+		return
+	}
+	m.Errors = append(m.Errors, e)
+}
+
+//messagePrefix returns the constant start of a message built by expr, and
+//whether it is only a prefix of the message
+func messagePrefix(expr ast.Expr) (string, bool) {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		message, _ := stringLiteral(x)
+		return message, false
+	case *ast.BinaryExpr:
+		if x.Op != token.ADD {
+			return "", false
+		}
+		prefix, _ := messagePrefix(x.X)
+		return prefix, true
+	case *ast.CallExpr:
+		fun := gotypes.ExprString(x.Fun)
+		if (fun != "fmt.Sprintf" && fun != "fmt.Errorf") || len(x.Args) == 0 {
+			return "", false
+		}
+		format, ok := stringLiteral(x.Args[0])
+		if !ok {
+			return "", false
+		}
+		if i := strings.Index(format, "%"); i >= 0 {
+			return format[:i], true
+		}
+		return format, false
+	}
+	return "", false
+}
+
+//stringLiteral returns the value of expr when it is a string literal
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+//groupErrors combines the Code and Error methods recorded for each error
+//type. Types with a code but no recognisable message are reported, as
+//the bridge cannot tell them apart from other errors.
+func groupErrors(diags *diagnostics.List, g *types.GoType) {
+	errs := make([]types.GoError, 0, len(g.Errors))
+	typed := make(map[string]int)
+	for _, e := range g.Errors {
+		if !e.Typed {
+			errs = append(errs, e)
+			continue
+		}
+		i, ok := typed[e.Name]
+		if !ok {
+			i = len(errs)
+			typed[e.Name] = i
+			errs = append(errs, types.GoError{Name: e.Name, Typed: true, Pos: e.Pos})
+			if spec, ok := g.Types[e.Name]; ok {
+				errs[i].Code = spec.Directives.Code
+				errs[i].Pos = spec.Pos
+			}
+		}
+		if e.Code != "" && errs[i].Code == "" {
+			errs[i].Code = e.Code
+		}
+		if e.Message != "" {
+			errs[i].Message, errs[i].Prefix = e.Message, e.Prefix
+		}
+	}
+	g.Errors = make([]types.GoError, 0, len(errs))
+	for _, e := range errs {
+		if e.Code == "" {
+			continue
+		}
+		if e.Message == "" {
+			diags.Warnf(e.Pos, diagnostics.CodeNotBridged,
+				"%s: the start of its Error message is not constant, so code %s cannot be matched", e.Name, e.Code)
+			continue
+		}
+		g.Errors = append(g.Errors, e)
+	}
+}
@@ -2,12 +2,18 @@ package goparser
 
 import (
 	"go/ast"
+	"go/constant"
 	"go/parser"
+	"go/scanner"
 	"go/token"
+	gotypes "go/types"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/steve-winter/reactgonative/diagnostics"
 	"github.com/steve-winter/reactgonative/types"
 )
 
@@ -15,31 +21,58 @@ const (
 	gopath = "GOPATH"
 )
 
-//Parsing locates the Go package at pkgIdentifier and generates an array of GoType representing each package, or an error
-func Parsing(pkgIdentifier string) ([]types.GoType, error) {
-	pkgs, e := parsePackage(pkgIdentifier)
+//Parsing locates the Go package at pkgIdentifier and generates an array of GoType representing each package, or an error.
+//The files of a package are merged into a single GoType, in file name order,
+//before constants of named integer types are grouped into enums and the
+//methods of typed errors are combined.
+//Syntax errors and directive problems are added to diags, which may be nil.
+func Parsing(pkgIdentifier string, diags *diagnostics.List) ([]types.GoType, error) {
+	fset := token.NewFileSet()
+	pkgs, e := parsePackage(fset, pkgIdentifier)
 	if e != nil {
+		reportParseError(e, diags)
 		return []types.GoType{}, e
 	}
 	typeList := make([]types.GoType, 0)
 	for _, pkg := range pkgs {
-		pkgName := pkg.Name
-		for name, f := range pkg.Files {
+		if strings.HasSuffix(pkg.Name, "_test") {
+			continue
+		}
+		names := make([]string, 0, len(pkg.Files))
+		for name := range pkg.Files {
 			if !strings.HasSuffix(name, "_test.go") {
-				typeList = append(typeList, parseFile(f, pkgName))
+				names = append(names, name)
 			}
 		}
+		sort.Strings(names)
+		pkgType := types.GoType{PackageName: pkg.Name, ImportPath: importPath(pkgIdentifier)}
+		for _, name := range names {
+			pkgType.Merge(parseFile(fset, diags, pkg.Files[name], pkg.Name))
+		}
+		pkgType.GroupEnums()
+		groupErrors(diags, &pkgType)
+		typeList = append(typeList, pkgType)
 	}
 	return typeList, nil
 }
 
-func parsePackage(pkgIdentifier string) (pkgs map[string]*ast.Package, first error) {
+func parsePackage(fset *token.FileSet, pkgIdentifier string) (pkgs map[string]*ast.Package, first error) {
 	folder := buildPackageFolder(pkgIdentifier)
-	fset := token.NewFileSet()
-	pkgs, e := parser.ParseDir(fset, folder, nil, 0)
+	pkgs, e := parser.ParseDir(fset, folder, nil, parser.ParseComments)
 	return pkgs, e
 }
 
+//reportParseError adds a positioned diagnostic for each syntax error in e
+func reportParseError(e error, diags *diagnostics.List) {
+	if list, ok := e.(scanner.ErrorList); ok {
+		for _, err := range list {
+			diags.Errorf(err.Pos, diagnostics.CodeParse, "%s", err.Msg)
+		}
+		return
+	}
+	diags.Errorf(token.Position{}, diagnostics.CodeParse, "%s", e.Error())
+}
+
 func buildPackageFolder(pkgIdentifier string) string {
 	if len(os.Getenv(gopath)) == 0 {
 		panic("GOPATH is not set")
@@ -49,15 +82,27 @@ func buildPackageFolder(pkgIdentifier string) string {
 	return folder
 }
 
-func parseFile(f *ast.File, pkgName string) types.GoType {
+func parseFile(fset *token.FileSet, diags *diagnostics.List, f *ast.File, pkgName string) types.GoType {
 	m := types.GoType{}
 	m.PackageName = pkgName
+	known := make(map[string]constant.Value)
 	// Inspect the AST and print all identifiers and literals.
 	ast.Inspect(f, func(n ast.Node) bool {
 		switch x := n.(type) {
 		case *ast.FuncDecl:
 			//Function declared
-			parseFunc(x, &m)
+			parseFunc(fset, diags, x, &m)
+			parseErrorMethod(fset, x, &m)
+			return false
+		case *ast.GenDecl:
+			//Imports declared
+			parseImports(x, &m)
+			//Type declared
+			parseTypeSpecs(fset, diags, x, &m)
+			//Constants declared
+			parseConstants(fset, diags, x, &m, known)
+			//Sentinel errors declared
+			parseSentinelErrors(fset, diags, x, &m)
 			// case *ast.Package:
 			// case *ast.FieldList:
 			// case *ast.BasicLit:
@@ -73,9 +118,25 @@ func parseFile(f *ast.File, pkgName string) types.GoType {
 	return m
 }
 
-func parseFunc(x *ast.FuncDecl, m *types.GoType) {
+//position resolves pos against fset, returning an empty position when no
+//file set is available
+func position(fset *token.FileSet, pos token.Pos) token.Position {
+	if fset == nil {
+		return token.Position{}
+	}
+	return fset.Position(pos)
+}
+
+func parseFunc(fset *token.FileSet, diags *diagnostics.List, x *ast.FuncDecl, m *types.GoType) {
 	if x.Name.IsExported() {
 		parseFuncName(x, m)
+		f := &m.Functions[len(m.Functions)-1]
+		f.Directives = parseDirectives(fset, diags, x.Doc)
+		f.Doc = docText(x.Doc)
+		f.Pos = position(fset, x.Name.Pos())
+		if x.Recv != nil && len(x.Recv.List) > 0 {
+			f.Receiver = gotypes.ExprString(x.Recv.List[0].Type)
+		}
 		parseParams(x, m)
 		parseReturn(x, m)
 	} else {
@@ -90,38 +151,188 @@ func parseFuncName(x *ast.FuncDecl, m *types.GoType) {
 	})
 }
 
+//fieldParams flattens a field list into one GoParams per declared name.
+//Unnamed fields produce a single GoParams with a blank name.
+func fieldParams(fields *ast.FieldList) []types.GoParams {
+	params := make([]types.GoParams, 0)
+	if fields == nil {
+		return params
+	}
+	for _, field := range fields.List {
+		t := gotypes.ExprString(field.Type)
+		if len(field.Names) == 0 {
+			params = append(params, types.GoParams{T: t})
+		}
+		for _, name := range field.Names {
+			params = append(params, types.GoParams{Name: name.Name, T: t})
+		}
+	}
+	return params
+}
+
 func parseParams(x *ast.FuncDecl, m *types.GoType) {
 	if x.Type.Params != nil {
-		for _, parameterList := range x.Type.Params.List {
-			m.Functions[len(m.Functions)-1].Params = append(m.Functions[len(m.Functions)-1].Params,
-				types.GoParams{})
-			switch x := parameterList.Type.(type) {
-			case *ast.Ident:
-				paramsLength := len(m.Functions[len(m.Functions)-1].Params)
-				m.Functions[len(m.Functions)-1].Params[paramsLength-1].T = x.Name
-				for _, parameterName := range parameterList.Names {
-					m.Functions[len(m.Functions)-1].Params[paramsLength-1].Name = parameterName.Name
-				}
+		m.Functions[len(m.Functions)-1].Params = fieldParams(x.Type.Params)
+	}
+}
+
+//parseReturn records every result of the function, and the first non error
+//result as the functions return type
+func parseReturn(x *ast.FuncDecl, m *types.GoType) {
+	f := &m.Functions[len(m.Functions)-1]
+	f.Results = fieldParams(x.Type.Results)
+	m.Returns = append(m.Returns, f.Return())
+}
+
+//parseImports records the path of each import, with its explicit name if any
+func parseImports(x *ast.GenDecl, m *types.GoType) {
+	if x.Tok != token.IMPORT {
+		return
+	}
+	for _, spec := range x.Specs {
+		importSpec, ok := spec.(*ast.ImportSpec)
+		if !ok {
+			continue
+		}
+		path, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if m.Imports == nil {
+			m.Imports = make(map[string]string)
+		}
+		m.Imports[path] = ""
+		if importSpec.Name != nil {
+			m.Imports[path] = importSpec.Name.Name
+		}
+	}
+}
+
+func parseTypeSpecs(fset *token.FileSet, diags *diagnostics.List, x *ast.GenDecl, m *types.GoType) {
+	if x.Tok != token.TYPE {
+		return
+	}
+	for _, spec := range x.Specs {
+		typeSpec, ok := spec.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+		doc := typeSpec.Doc
+		if doc == nil && len(x.Specs) == 1 {
+			doc = x.Doc
+		}
+		if m.Types == nil {
+			m.Types = make(map[string]types.GoTypeSpec)
+		}
+		t := types.GoTypeSpec{
+			Name:       typeSpec.Name.Name,
+			Kind:       types.KindOther,
+			Doc:        docText(doc),
+			Directives: parseDirectives(fset, diags, doc),
+			Pos:        position(fset, typeSpec.Name.Pos()),
+		}
+		switch u := typeSpec.Type.(type) {
+		case *ast.StructType:
+			t.Kind = types.KindStruct
+		case *ast.InterfaceType:
+			t.Kind = types.KindInterface
+			parseInterfaceMethods(fset, u, &t)
+		case *ast.Ident:
+			if types.IsBasicType(u.Name) {
+				t.Kind = types.KindBasic
+				t.Underlying = u.Name
 			}
 		}
+		m.Types[t.Name] = t
 	}
 }
 
-func parseReturn(x *ast.FuncDecl, m *types.GoType) {
-	m.Returns = append(m.Returns, types.GoParams{})
-	if x.Type.Results != nil {
-		for _, parameterList := range x.Type.Results.List {
-			switch v := parameterList.Type.(type) {
-			case *ast.Ident:
-				// specialType := parameterList.Type.(*ast.Ident)
-				m.Returns[len(m.Returns)-1].T = v.Name
-				for _, parameterName := range parameterList.Names {
-					m.Returns[len(m.Returns)-1].Name = parameterName.Name
-				}
-			case *ast.ArrayType:
-				// fmt.Printf("ARRAYTYPE %s uuu %v\n", x.Name.String(), v.Elt.(*ast.SelectorExpr).Sel.Obj)
+func parseInterfaceMethods(fset *token.FileSet, x *ast.InterfaceType, t *types.GoTypeSpec) {
+	if x.Methods == nil {
+		return
+	}
+	for _, field := range x.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			t.Embedded = append(t.Embedded, gotypes.ExprString(field.Type))
+			continue
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			t.Methods = append(t.Methods, types.GoFunction{
+				Name:    name.Name,
+				Doc:     docText(field.Doc),
+				Params:  fieldParams(funcType.Params),
+				Results: fieldParams(funcType.Results),
+				Pos:     position(fset, name.Pos()),
+			})
+		}
+	}
+}
 
+//docText is the text of a doc comment, without its directives
+func docText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+//parseDirectives reads the //reactgonative: lines of a doc comment.
+//Unknown or malformed directives are reported as warnings and otherwise ignored.
+func parseDirectives(fset *token.FileSet, diags *diagnostics.List, doc *ast.CommentGroup) types.GoDirectives {
+	d := types.GoDirectives{}
+	if doc == nil {
+		return d
+	}
+	var threadPos token.Position
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, types.DirectivePrefix) {
+			continue
+		}
+		pos := position(fset, c.Slash)
+		fields := strings.Fields(strings.TrimPrefix(c.Text, types.DirectivePrefix))
+		if len(fields) == 0 {
+			diags.Warnf(pos, diagnostics.CodeUnknownDirective, "empty reactgonative directive")
+			continue
+		}
+		switch fields[0] {
+		case "ignore":
+			d.Ignore = true
+		case "sync":
+			d.Sync = true
+		case "name":
+			if len(fields) != 2 {
+				diags.Warnf(pos, diagnostics.CodeInvalidDirective, "reactgonative:name expects a single name")
+				continue
+			}
+			d.Name = fields[1]
+		case "thread":
+			if len(fields) != 2 {
+				diags.Warnf(pos, diagnostics.CodeInvalidDirective, "reactgonative:thread expects a single thread")
+				continue
 			}
+			if !types.IsThread(fields[1]) {
+				diags.Warnf(pos, diagnostics.CodeInvalidDirective,
+					"unknown thread %q, expected inline, background or pool", fields[1])
+				continue
+			}
+			d.Thread, threadPos = fields[1], pos
+		case "code":
+			if len(fields) != 2 {
+				diags.Warnf(pos, diagnostics.CodeInvalidDirective, "reactgonative:code expects a single code")
+				continue
+			}
+			d.Code = fields[1]
+		default:
+			diags.Warnf(pos, diagnostics.CodeUnknownDirective, "unknown directive reactgonative:%s", fields[0])
 		}
 	}
+	if d.Sync && d.Thread != "" {
+		diags.Warnf(threadPos, diagnostics.CodeInvalidDirective,
+			"reactgonative:thread %s is ignored, sync functions run on the calling JS thread", d.Thread)
+	}
+	return d
 }
@@ -2,11 +2,16 @@ package goparser
 
 import (
 	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/diagnostics"
 	"github.com/steve-winter/reactgonative/types"
 )
 
@@ -68,7 +73,7 @@ func TestParseFile(t *testing.T) {
 	Convey("Given empty file", t, func() {
 		file := &ast.File{}
 		Convey("When only a package name pkg is used", func() {
-			goType := parseFile(file, "pkg")
+			goType := parseFile(nil, nil, file, "pkg")
 			Convey("Then the package name on the gotype is pkg", func() {
 				So(goType.PackageName, ShouldEqual, "pkg")
 			})
@@ -164,7 +169,7 @@ func TestParseFile(t *testing.T) {
 			},
 		}
 		Convey("When an exported and unexported function is set", func() {
-			goType := parseFile(file, "pkg")
+			goType := parseFile(nil, nil, file, "pkg")
 			Convey("Then the number of functions equals 1", func() {
 				So(len(goType.Functions), ShouldEqual, 2)
 			})
@@ -191,21 +196,21 @@ func TestParsePackage(t *testing.T) {
 	Convey("Given a package directory of the goparser", t, func() {
 		pkgDir := "github.com/steve-winter/reactgonative/goparser"
 		Convey("When parse package is called", func() {
-			pkgs, err := parsePackage(pkgDir)
+			pkgs, err := parsePackage(token.NewFileSet(), pkgDir)
 			Convey("Then there are no errors", func() {
 				So(err, ShouldBeNil)
 			})
 			Convey("And there is 1 package found", func() {
 				So(len(pkgs), ShouldEqual, 1)
 			})
-			Convey("And there are 2 files found", func() {
-				So(len(pkgs["goparser"].Files), ShouldEqual, 2)
+			Convey("And there are 5 files found", func() {
+				So(len(pkgs["goparser"].Files), ShouldEqual, 5)
 			})
-			Convey("And there are 10 declarations", func() {
+			Convey("And there are 18 declarations", func() {
 				fileName := filepath.Join(os.Getenv("GOPATH"), "src")
 				fileName = filepath.Join(fileName, pkgDir)
 				fileName = filepath.Join(fileName, "parsing.go")
-				So(len(pkgs["goparser"].Files[fileName].Decls), ShouldEqual, 10)
+				So(len(pkgs["goparser"].Files[fileName].Decls), ShouldEqual, 18)
 			})
 		})
 	})
@@ -215,41 +220,420 @@ func TestParsing(t *testing.T) {
 	Convey("Given a package directory of the goparser", t, func() {
 		pkgDir := "github.com/steve-winter/reactgonative/goparser"
 		Convey("When parsing is called", func() {
-			goTypes, err := Parsing(pkgDir)
+			goTypes, err := Parsing(pkgDir, nil)
 			Convey("Then there are no errors", func() {
 				So(err, ShouldBeNil)
 			})
 			Convey("And there is 1 package found", func() {
 				So(len(goTypes), ShouldEqual, 1)
 			})
-			Convey("And 1 exported function", func() {
-				So(len(goTypes[0].Functions), ShouldEqual, 1)
+			Convey("And 3 exported functions", func() {
+				So(len(goTypes[0].Functions), ShouldEqual, 3)
+			})
+			Convey("And the import path is recorded", func() {
+				So(goTypes[0].ImportPath, ShouldEqual, pkgDir)
+			})
+			Convey("And the imports are recorded", func() {
+				So(goTypes[0].Imports, ShouldContainKey, "github.com/steve-winter/reactgonative/types")
+				So(goTypes[0].Imports, ShouldContainKey, "go/ast")
+				So(goTypes[0].Imports["go/types"], ShouldEqual, "gotypes")
 			})
 			Convey("And function name of Parsing", func() {
 				So(goTypes[0].Functions[0].Name, ShouldEqual, "Parsing")
 			})
-			Convey("And the function has 0 params", func() {
-				So(len(goTypes[0].Functions[0].Params), ShouldEqual, 1)
+			Convey("And the function has 2 params", func() {
+				So(len(goTypes[0].Functions[0].Params), ShouldEqual, 2)
 			})
 			Convey("And with a param name of pkgIdentifier with type of string", func() {
 				So(goTypes[0].Functions[0].Params[0].Name, ShouldEqual, "pkgIdentifier")
 				So(goTypes[0].Functions[0].Params[0].T, ShouldEqual, "string")
 			})
-			Convey("And return type is error (BUG)", func() {
-				//BUG - Issue #9. Only supports single return type at present
-				So(goTypes[0].Returns[0].T, ShouldEqual, "error")
+			Convey("And with a param name of diags with type of *diagnostics.List", func() {
+				So(goTypes[0].Functions[0].Params[1].Name, ShouldEqual, "diags")
+				So(goTypes[0].Functions[0].Params[1].T, ShouldEqual, "*diagnostics.List")
+			})
+			Convey("And return type is the non error result", func() {
+				So(goTypes[0].Returns[0].T, ShouldEqual, "[]types.GoType")
+			})
+			Convey("And both results are recorded", func() {
+				So(len(goTypes[0].Functions[0].Results), ShouldEqual, 2)
+				So(goTypes[0].Functions[0].Results[1].T, ShouldEqual, "error")
 			})
 		})
 	})
 	Convey("Given a package doesnt exist", t, func() {
+		diags := &diagnostics.List{}
 		Convey("When parsing is called", func() {
-			pkgs, err := Parsing("384738h932h392h32")
+			pkgs, err := Parsing("384738h932h392h32", diags)
 			Convey("Then an error is returned", func() {
 				So(err.Error(), ShouldEndWith, "no such file or directory")
 			})
 			Convey("And no packages returned", func() {
 				So(len(pkgs), ShouldEqual, 0)
 			})
+			Convey("And an error diagnostic is added", func() {
+				So(diags.Count(diagnostics.Error), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func TestExpand(t *testing.T) {
+	realGoPath := os.Getenv("GOPATH")
+	Convey("Given a GOPATH with nested packages", t, func() {
+		root := "/tmp/reactgonative/expand"
+		os.RemoveAll(root)
+		for _, f := range []string{"a/a.go", "a/b/b.go", "a/c/c_test.go", "a/testdata/t.go", "a/_skip/s.go"} {
+			path := filepath.Join(root, "src/example.com", f)
+			os.MkdirAll(filepath.Dir(path), 0777)
+			ioutil.WriteFile(path, []byte("package x\n"), 0666)
+		}
+		os.Setenv("GOPATH", root)
+		Convey("When a recursive pattern is expanded", func() {
+			paths, err := Expand([]string{"example.com/a/...", "example.com/a/b"})
+			Convey("Then every package below it is matched once", func() {
+				So(err, ShouldBeNil)
+				So(paths, ShouldResemble, []string{"example.com/a", "example.com/a/b"})
+			})
+		})
+		Convey("When a pattern matches nothing", func() {
+			_, err := Expand([]string{"example.com/a/c"})
+			Convey("Then an error is returned", func() {
+				So(err.Error(), ShouldEqual, "example.com/a/c matched no packages")
+			})
+		})
+		Reset(func() {
+			os.Setenv("GOPATH", realGoPath)
+		})
+	})
+}
+
+func TestParseDirectives(t *testing.T) {
+	Convey("Given a doc comment with directives", t, func() {
+		doc := &ast.CommentGroup{
+			List: []*ast.Comment{
+				&ast.Comment{Text: "// Greetings says hello"},
+				&ast.Comment{Text: "//reactgonative:sync"},
+				&ast.Comment{Text: "//reactgonative:name hello"},
+				&ast.Comment{Text: "//reactgonative:thread background"},
+				&ast.Comment{Text: "//reactgonative:unknown value"},
+			},
+		}
+		Convey("When the directives are parsed", func() {
+			diags := &diagnostics.List{}
+			d := parseDirectives(nil, diags, doc)
+			Convey("Then sync is set", func() {
+				So(d.Sync, ShouldBeTrue)
+			})
+			Convey("And ignore is not set", func() {
+				So(d.Ignore, ShouldBeFalse)
+			})
+			Convey("And the name is hello", func() {
+				So(d.Name, ShouldEqual, "hello")
+			})
+			Convey("And the thread is background", func() {
+				So(d.Thread, ShouldEqual, "background")
+			})
+			Convey("And the unknown directive is warned about", func() {
+				So(diags.Count(diagnostics.Warning), ShouldEqual, 2)
+				So(diags.Diagnostics()[0].Code, ShouldEqual, diagnostics.CodeUnknownDirective)
+			})
+			Convey("And the thread ignored by the sync function is warned about", func() {
+				So(diags.Diagnostics()[1].Code, ShouldEqual, diagnostics.CodeInvalidDirective)
+				So(diags.Diagnostics()[1].Message, ShouldEqual, "reactgonative:thread background is ignored, sync functions run on the calling JS thread")
+			})
+		})
+	})
+	Convey("Given no doc comment", t, func() {
+		Convey("When the directives are parsed", func() {
+			d := parseDirectives(nil, nil, nil)
+			Convey("Then no directives are set", func() {
+				So(d, ShouldResemble, types.GoDirectives{})
+			})
+		})
+	})
+	Convey("Given a file with an ignored type and function", t, func() {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "directives.go", `package pkg
+
+//reactgonative:ignore
+type Hidden struct{}
+
+//reactgonative:ignore
+func Skipped() {}
+
+//Greet says hello.
+//reactgonative:name hi
+func Greet(h *Hidden) {}
+`, parser.ParseComments)
+		So(err, ShouldBeNil)
+		Convey("When the file is parsed", func() {
+			goType := parseFile(fset, nil, file, "pkg")
+			Convey("Then the type directive is recorded", func() {
+				So(goType.Types["Hidden"].Directives.Ignore, ShouldBeTrue)
+			})
+			Convey("And the function directives are recorded", func() {
+				So(goType.Functions[0].Directives.Ignore, ShouldBeTrue)
+				So(goType.Functions[1].Directives.Name, ShouldEqual, "hi")
+			})
+			Convey("And doc comments are recorded without their directives", func() {
+				So(goType.Functions[1].Doc, ShouldEqual, "Greet says hello.")
+				So(goType.Types["Hidden"].Doc, ShouldEqual, "")
+			})
 		})
 	})
 }
+
+func TestReportParseError(t *testing.T) {
+	Convey("Given a file with a syntax error", t, func() {
+		_, err := parser.ParseFile(token.NewFileSet(), "broken.go", "package pkg\n\nfunc Broken( {\n", 0)
+		So(err, ShouldNotBeNil)
+		Convey("When the error is reported", func() {
+			diags := &diagnostics.List{}
+			reportParseError(err, diags)
+			Convey("Then an error diagnostic is positioned in the file", func() {
+				d := diags.Diagnostics()[0]
+				So(d.Severity, ShouldEqual, diagnostics.Error)
+				So(d.Code, ShouldEqual, diagnostics.CodeParse)
+				So(d.Pos.Filename, ShouldEqual, "broken.go")
+				So(d.Pos.Line, ShouldEqual, 3)
+			})
+		})
+	})
+}
+
+func TestParseInterfaceMethods(t *testing.T) {
+	Convey("Given a file with an exported interface", t, func() {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "iface.go", `package pkg
+
+type Counter interface {
+	fmt.Stringer
+	OnProgress(n int, label string)
+	hidden()
+}
+`, parser.ParseComments)
+		So(err, ShouldBeNil)
+		Convey("When the file is parsed", func() {
+			goType := parseFile(fset, nil, file, "pkg")
+			spec := goType.Types["Counter"]
+			Convey("Then it is an interface", func() {
+				So(spec.Kind, ShouldEqual, types.KindInterface)
+			})
+			Convey("And its exported methods are recorded with params", func() {
+				So(len(spec.Methods), ShouldEqual, 1)
+				So(spec.Methods[0].Name, ShouldEqual, "OnProgress")
+				So(len(spec.Methods[0].Params), ShouldEqual, 2)
+				So(spec.Methods[0].Pos.Line, ShouldEqual, 5)
+			})
+			Convey("And embedded interfaces are recorded", func() {
+				So(spec.Embedded, ShouldResemble, []string{"fmt.Stringer"})
+			})
+		})
+	})
+}
+
+func TestParseConstants(t *testing.T) {
+	Convey("Given a file with exported constants", t, func() {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "consts.go", `package pkg
+
+const Version = "1.0"
+
+const (
+	MaxItems = 1 << 4
+	limit    = MaxItems * 2
+	Limit    = limit + 1
+	Ratio    = 1.5
+	Enabled  = Limit > 10
+	Timeout  int32 = 30
+	Letter   = 'a'
+)
+
+const (
+	First = iota
+	Second
+)
+
+//reactgonative:ignore
+const Secret = "x"
+
+const Dynamic = len(Version)
+`, parser.ParseComments)
+		So(err, ShouldBeNil)
+		Convey("When the file is parsed", func() {
+			diags := &diagnostics.List{}
+			goType := parseFile(fset, diags, file, "pkg")
+			byName := make(map[string]types.GoConstant)
+			for _, c := range goType.Constants {
+				byName[c.Name] = c
+			}
+			Convey("Then only exported constants with known values are recorded", func() {
+				So(len(goType.Constants), ShouldEqual, 10)
+				So(byName, ShouldNotContainKey, "limit")
+				So(byName, ShouldNotContainKey, "Dynamic")
+			})
+			Convey("And untyped constants take their default type", func() {
+				So(byName["Version"].T, ShouldEqual, "string")
+				So(byName["Version"].Value, ShouldEqual, `"1.0"`)
+				So(byName["MaxItems"].T, ShouldEqual, "int")
+				So(byName["MaxItems"].Value, ShouldEqual, "16")
+				So(byName["Ratio"].T, ShouldEqual, "float64")
+				So(byName["Enabled"].T, ShouldEqual, "bool")
+				So(byName["Enabled"].Value, ShouldEqual, "true")
+				So(byName["Letter"].T, ShouldEqual, "rune")
+			})
+			Convey("And earlier constants can be referenced", func() {
+				So(byName["Limit"].Value, ShouldEqual, "33")
+			})
+			Convey("And explicit types are kept", func() {
+				So(byName["Timeout"].T, ShouldEqual, "int32")
+			})
+			Convey("And iota and implicit repetition are evaluated", func() {
+				So(byName["First"].Value, ShouldEqual, "0")
+				So(byName["Second"].Value, ShouldEqual, "1")
+			})
+			Convey("And directives are read from the declaration", func() {
+				So(byName["Secret"].Directives.Ignore, ShouldBeTrue)
+			})
+			Convey("And a constant that cannot be evaluated is reported", func() {
+				d := diags.Diagnostics()[0]
+				So(d.Code, ShouldEqual, diagnostics.CodeNotBridged)
+				So(d.Pos.Line, ShouldEqual, 23)
+			})
+		})
+	})
+}
+
+func TestEvalConst(t *testing.T) {
+	Convey("Given constant expressions", t, func() {
+		eval := func(src string) (string, bool) {
+			expr, err := parser.ParseExpr(src)
+			So(err, ShouldBeNil)
+			v, ok := evalConst(expr, 0, map[string]constant.Value{})
+			if !ok {
+				return "", false
+			}
+			return v.ExactString(), true
+		}
+		Convey("Then valid expressions are evaluated", func() {
+			for src, want := range map[string]string{
+				"7 / 2":            "3",
+				"7.0 / 2":          "7/2",
+				"7 % 4":            "3",
+				`"a" + "b"`:        `"ab"`,
+				"true && !false":   "true",
+				"1 < 2.5":          "true",
+				"^1":               "-2",
+				"-(1 << 3)":        "-8",
+				`"a" == "b"`:       "false",
+				"true != false":    "true",
+				"(1 + 2i) == 1+2i": "true",
+			} {
+				got, ok := eval(src)
+				So(src+" "+got, ShouldEqual, src+" "+want)
+				So(ok, ShouldBeTrue)
+			}
+		})
+		Convey("And expressions that do not type check are skipped", func() {
+			for _, src := range []string{
+				"1.5 / 0",
+				"1 / 0",
+				"1 % 0",
+				"(1 + 2i) / 0",
+				"!1",
+				"-true",
+				"^1.5",
+				"true + 1",
+				`"a" - "b"`,
+				`"a" + 1`,
+				"1 % 1.5",
+				"1.5 & 1",
+				"1 && true",
+				"true < false",
+				"1 < true",
+				`"a" == 1`,
+				"1i < 2i",
+				"1.5 << 2",
+				"1 << 1.5",
+				"1 << -1",
+				"1 << 100000",
+			} {
+				got, ok := eval(src)
+				So(src+" "+got, ShouldEqual, src+" ")
+				So(ok, ShouldBeFalse)
+			}
+		})
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	Convey("Given a file declaring sentinel and typed errors", t, func() {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "errors.go", `package pkg
+
+var ErrNotFound = errors.New("not found")
+
+//reactgonative:code E_GONE
+var ErrRemoved = fmt.Errorf("removed")
+
+var ErrFormatted = fmt.Errorf("bad %d", 1)
+
+type QuotaError struct{ Limit int }
+
+func (e *QuotaError) Error() string { return fmt.Sprintf("quota exceeded: %d", e.Limit) }
+
+func (e *QuotaError) Code() string { return "E_QUOTA" }
+
+type OpaqueError struct{ msg string }
+
+func (e OpaqueError) Error() string { return e.msg }
+
+func (e OpaqueError) Code() string { return "E_OPAQUE" }
+`, parser.ParseComments)
+		So(err, ShouldBeNil)
+		Convey("When the file is parsed and errors grouped", func() {
+			diags := &diagnostics.List{}
+			goType := parseFile(fset, diags, file, "pkg")
+			groupErrors(diags, &goType)
+			Convey("Then sentinel errors with constant messages are recorded", func() {
+				So(goType.Errors[0].Code, ShouldEqual, "E_NOT_FOUND")
+				So(goType.Errors[0].Message, ShouldEqual, "not found")
+				So(goType.Errors[0].Prefix, ShouldBeFalse)
+			})
+			Convey("And the code directive overrides the derived code", func() {
+				So(goType.Errors[1].Code, ShouldEqual, "E_GONE")
+			})
+			Convey("And typed errors are matched by the start of their message", func() {
+				So(len(goType.Errors), ShouldEqual, 3)
+				So(goType.Errors[2].Name, ShouldEqual, "QuotaError")
+				So(goType.Errors[2].Code, ShouldEqual, "E_QUOTA")
+				So(goType.Errors[2].Message, ShouldEqual, "quota exceeded: ")
+				So(goType.Errors[2].Prefix, ShouldBeTrue)
+			})
+			Convey("And typed errors without a constant message are reported", func() {
+				d := diags.Diagnostics()[0]
+				So(d.Code, ShouldEqual, diagnostics.CodeNotBridged)
+				So(d.Message, ShouldContainSubstring, "OpaqueError")
+			})
+		})
+	})
+}
+
+func FuzzParseFile(f *testing.F) {
+	f.Add("package a\n\nfunc Greet(name string) (string, error) { return name, nil }\n")
+	f.Add("package a\n\ntype Level int\n\nconst (\n\tLow Level = iota\n\tHigh\n)\n")
+	f.Add("package a\n\nimport \"errors\"\n\nvar ErrA = errors.New(\"a\")\n\ntype E struct{}\n\nfunc (E) Error() string { return \"e: \" + \"x\" }\n")
+	f.Add("package a\n\n//reactgonative:thread\n//reactgonative:name a b\nfunc (c *C) A(func(), ...int) {}\n\nconst X = 1 << 70 / 0\n")
+	f.Fuzz(func(t *testing.T, src string) {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "fuzz.go", src, parser.ParseComments)
+		if err != nil {
+			return
+		}
+		diags := &diagnostics.List{}
+		g := parseFile(fset, diags, file, file.Name.Name)
+		g.GroupEnums()
+		groupErrors(diags, &g)
+	})
+}
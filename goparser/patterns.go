@@ -0,0 +1,116 @@
+package goparser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//Expand resolves each of patterns to the import paths of Go packages under
+//GOPATH, in order and without duplicates.
+//A pattern is an import path, or a directory relative to the working
+//directory when it starts with a dot. Either may end in /... to match every
+//package in or below it, skipping testdata, vendor and directories starting
+//with a dot or an underscore.
+//Returns an error if a pattern matches no packages.
+func Expand(patterns []string) ([]string, error) {
+	src := buildPackageFolder("")
+	seen := make(map[string]bool)
+	paths := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		base, recursive := pattern, false
+		if pattern == "..." || strings.HasSuffix(pattern, "/...") {
+			base, recursive = strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/"), true
+		}
+		dir, err := patternFolder(src, base)
+		if err != nil {
+			return paths, err
+		}
+		dirs := []string{dir}
+		if recursive {
+			dirs, err = packageFolders(dir)
+			if err != nil {
+				return paths, err
+			}
+		}
+		matched := false
+		for _, d := range dirs {
+			if !hasGoFiles(d) {
+				continue
+			}
+			rel, err := filepath.Rel(src, d)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				return paths, fmt.Errorf("%s: %s is not under %s", pattern, d, src)
+			}
+			matched = true
+			path := filepath.ToSlash(rel)
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+		if !matched {
+			return paths, fmt.Errorf("%s matched no packages", pattern)
+		}
+	}
+	return paths, nil
+}
+
+//Folder is the directory holding the package at path, an import path
+//returned by Expand
+func Folder(path string) string {
+	return buildPackageFolder(path)
+}
+
+//importPath is the import path of the package identified by pkgIdentifier
+func importPath(pkgIdentifier string) string {
+	return strings.Trim(filepath.ToSlash(filepath.Clean(pkgIdentifier)), "/")
+}
+
+//patternFolder is the directory the base of a pattern names, under src
+//unless relative to the working directory
+func patternFolder(src string, base string) (string, error) {
+	if base != "." && base != ".." && !strings.HasPrefix(base, "./") && !strings.HasPrefix(base, "../") {
+		return filepath.Join(src, base), nil
+	}
+	return filepath.Abs(base)
+}
+
+//packageFolders returns dir and every directory below it the go tool would
+//consider for packages
+func packageFolders(dir string) ([]string, error) {
+	dirs := make([]string, 0)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != dir && (name == "testdata" || name == "vendor" ||
+			strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, path)
+		return nil
+	})
+	return dirs, err
+}
+
+//hasGoFiles identifies whether dir holds any Go file other than tests
+func hasGoFiles(dir string) bool {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, f := range files {
+		name := f.Name()
+		if !f.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			return true
+		}
+	}
+	return false
+}
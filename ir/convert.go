@@ -0,0 +1,287 @@
+package ir
+
+import (
+	"go/token"
+	"path/filepath"
+	"sort"
+
+	"github.com/steve-winter/reactgonative/types"
+)
+
+//FromGoType converts the parsed package g into its IR. Options the
+//configuration sets, such as the javapkg and enum representations, are
+//left to the run reading the IR.
+func FromGoType(g types.GoType) Package {
+	p := Package{Name: g.PackageName, ImportPath: g.ImportPath}
+	paths := make([]string, 0, len(g.Imports))
+	for path := range g.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		p.Imports = append(p.Imports, Import{Path: path, Name: g.Imports[path]})
+	}
+	for _, f := range g.Functions {
+		p.Functions = append(p.Functions, fromFunction(f))
+	}
+	enums := make(map[string]bool)
+	for _, e := range g.Enums {
+		enums[e.Name] = true
+		values := make([]Constant, 0, len(e.Values))
+		for _, c := range e.Values {
+			values = append(values, fromConstant(c))
+		}
+		p.Enums = append(p.Enums, Enum{
+			Name:       e.Name,
+			Doc:        e.Doc,
+			Underlying: e.Underlying,
+			Values:     values,
+			Directives: fromDirectives(e.Directives),
+			Pos:        fromPos(e.Pos),
+		})
+	}
+	names := make([]string, 0, len(g.Types))
+	for name := range g.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		spec := g.Types[name]
+		directives, pos := fromDirectives(spec.Directives), fromPos(spec.Pos)
+		switch {
+		case enums[name]:
+			//Recreated from the enum
+		case spec.Kind == types.KindStruct:
+			p.Structs = append(p.Structs, Struct{Name: name, Doc: spec.Doc, Directives: directives, Pos: pos})
+		case spec.Kind == types.KindInterface:
+			methods := make([]Function, 0, len(spec.Methods))
+			for _, m := range spec.Methods {
+				methods = append(methods, fromFunction(m))
+			}
+			p.Interfaces = append(p.Interfaces, Interface{
+				Name:       name,
+				Doc:        spec.Doc,
+				Methods:    methods,
+				Embedded:   spec.Embedded,
+				Directives: directives,
+				Pos:        pos,
+			})
+		default:
+			p.Types = append(p.Types, Type{Name: name, Doc: spec.Doc, Underlying: spec.Underlying, Directives: directives, Pos: pos})
+		}
+	}
+	for _, c := range g.Constants {
+		p.Constants = append(p.Constants, fromConstant(c))
+	}
+	for _, e := range g.Errors {
+		p.Errors = append(p.Errors, Error{
+			Name:    e.Name,
+			Code:    e.Code,
+			Message: e.Message,
+			Prefix:  e.Prefix,
+			Typed:   e.Typed,
+			Pos:     fromPos(e.Pos),
+		})
+	}
+	return p
+}
+
+//GoType converts p into the package the generators take. The return type
+//of each function is derived from its results, as the parser does, and
+//positions are resolved against dir, the package directory, when set.
+func (p Package) GoType(dir string) types.GoType {
+	g := types.GoType{
+		PackageName: p.Name,
+		ImportPath:  p.ImportPath,
+		Functions:   make([]types.GoFunction, 0, len(p.Functions)),
+		Returns:     make([]types.GoParams, 0, len(p.Functions)),
+		Types:       make(map[string]types.GoTypeSpec),
+	}
+	for _, i := range p.Imports {
+		if g.Imports == nil {
+			g.Imports = make(map[string]string)
+		}
+		g.Imports[i.Path] = i.Name
+	}
+	for _, fn := range p.Functions {
+		f := fn.goFunction(dir)
+		g.Functions = append(g.Functions, f)
+		g.Returns = append(g.Returns, f.Return())
+	}
+	for _, s := range p.Structs {
+		g.Types[s.Name] = types.GoTypeSpec{
+			Name:       s.Name,
+			Doc:        s.Doc,
+			Kind:       types.KindStruct,
+			Directives: s.Directives.goDirectives(),
+			Pos:        s.Pos.goPos(dir),
+		}
+	}
+	for _, iface := range p.Interfaces {
+		spec := types.GoTypeSpec{
+			Name:       iface.Name,
+			Doc:        iface.Doc,
+			Kind:       types.KindInterface,
+			Embedded:   iface.Embedded,
+			Directives: iface.Directives.goDirectives(),
+			Pos:        iface.Pos.goPos(dir),
+		}
+		for _, m := range iface.Methods {
+			spec.Methods = append(spec.Methods, m.goFunction(dir))
+		}
+		g.Types[iface.Name] = spec
+	}
+	for _, t := range p.Types {
+		kind := types.KindOther
+		if types.IsBasicType(t.Underlying) {
+			kind = types.KindBasic
+		}
+		g.Types[t.Name] = types.GoTypeSpec{
+			Name:       t.Name,
+			Doc:        t.Doc,
+			Kind:       kind,
+			Underlying: t.Underlying,
+			Directives: t.Directives.goDirectives(),
+			Pos:        t.Pos.goPos(dir),
+		}
+	}
+	for _, e := range p.Enums {
+		enum := types.GoEnum{
+			Name:       e.Name,
+			Doc:        e.Doc,
+			Underlying: e.Underlying,
+			Directives: e.Directives.goDirectives(),
+			Pos:        e.Pos.goPos(dir),
+		}
+		for _, c := range e.Values {
+			enum.Values = append(enum.Values, c.goConstant(dir))
+		}
+		g.Enums = append(g.Enums, enum)
+		g.Types[e.Name] = types.GoTypeSpec{
+			Name:       e.Name,
+			Doc:        e.Doc,
+			Kind:       types.KindBasic,
+			Underlying: e.Underlying,
+			Directives: enum.Directives,
+			Pos:        enum.Pos,
+		}
+	}
+	for _, c := range p.Constants {
+		g.Constants = append(g.Constants, c.goConstant(dir))
+	}
+	for _, e := range p.Errors {
+		g.Errors = append(g.Errors, types.GoError{
+			Name:    e.Name,
+			Code:    e.Code,
+			Message: e.Message,
+			Prefix:  e.Prefix,
+			Typed:   e.Typed,
+			Pos:     e.Pos.goPos(dir),
+		})
+	}
+	return g
+}
+
+func fromFunction(f types.GoFunction) Function {
+	return Function{
+		Name:       f.Name,
+		Doc:        f.Doc,
+		Receiver:   f.Receiver,
+		Params:     fromParams(f.Params),
+		Results:    fromParams(f.Results),
+		Directives: fromDirectives(f.Directives),
+		Pos:        fromPos(f.Pos),
+	}
+}
+
+func (fn Function) goFunction(dir string) types.GoFunction {
+	return types.GoFunction{
+		Name:       fn.Name,
+		Doc:        fn.Doc,
+		Receiver:   fn.Receiver,
+		Params:     goParams(fn.Params),
+		Results:    goParams(fn.Results),
+		Directives: fn.Directives.goDirectives(),
+		Pos:        fn.Pos.goPos(dir),
+	}
+}
+
+func fromParams(params []types.GoParams) []Param {
+	if len(params) == 0 {
+		return nil
+	}
+	converted := make([]Param, 0, len(params))
+	for _, p := range params {
+		converted = append(converted, Param{Name: p.Name, Type: p.T})
+	}
+	return converted
+}
+
+func goParams(params []Param) []types.GoParams {
+	converted := make([]types.GoParams, 0, len(params))
+	for _, p := range params {
+		converted = append(converted, types.GoParams{Name: p.Name, T: p.Type})
+	}
+	return converted
+}
+
+func fromConstant(c types.GoConstant) Constant {
+	return Constant{
+		Name:       c.Name,
+		Type:       c.T,
+		Value:      c.Value,
+		Directives: fromDirectives(c.Directives),
+		Pos:        fromPos(c.Pos),
+	}
+}
+
+func (c Constant) goConstant(dir string) types.GoConstant {
+	return types.GoConstant{
+		Name:       c.Name,
+		T:          c.Type,
+		Value:      c.Value,
+		Directives: c.Directives.goDirectives(),
+		Pos:        c.Pos.goPos(dir),
+	}
+}
+
+//fromDirectives returns nil when no directive is set, leaving it out of the JSON
+func fromDirectives(d types.GoDirectives) *Directives {
+	if d == (types.GoDirectives{}) {
+		return nil
+	}
+	return &Directives{Ignore: d.Ignore, Sync: d.Sync, Name: d.Name, Thread: d.Thread, Code: d.Code}
+}
+
+func (d *Directives) goDirectives() types.GoDirectives {
+	if d == nil {
+		return types.GoDirectives{}
+	}
+	return types.GoDirectives{Ignore: d.Ignore, Sync: d.Sync, Name: d.Name, Thread: d.Thread, Code: d.Code}
+}
+
+//fromPos returns nil for an unknown position, leaving it out of the JSON.
+//Files of a package all sit in its directory, so only the file name is
+//kept, leaving the IR the same wherever the sources are checked out.
+func fromPos(pos token.Position) *Pos {
+	if !pos.IsValid() {
+		return nil
+	}
+	file := pos.Filename
+	if file != "" {
+		file = filepath.Base(file)
+	}
+	return &Pos{File: file, Line: pos.Line, Column: pos.Column}
+}
+
+//goPos joins the file name to dir, leaving absolute paths as read
+func (p *Pos) goPos(dir string) token.Position {
+	if p == nil {
+		return token.Position{}
+	}
+	file := p.File
+	if dir != "" && file != "" && !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	return token.Position{Filename: file, Line: p.Line, Column: p.Column}
+}
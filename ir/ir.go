@@ -0,0 +1,229 @@
+package ir
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/steve-winter/reactgonative/types"
+)
+
+//Version is the version of the IR written by this tool, and the only
+//version it reads. It changes whenever a change would mislead an older reader.
+const Version = 1
+
+//File is the root of an IR document, holding every package of a run
+type File struct {
+	Version  int       `json:"version"`
+	Packages []Package `json:"packages"`
+}
+
+//Package is a Go package to bridge. Name is the name in the package clause,
+//and ImportPath the path gomobile binds it from.
+//Imports lists the packages it imports, which link the types it uses from
+//other packages of the same run.
+type Package struct {
+	Name       string      `json:"name"`
+	ImportPath string      `json:"importPath,omitempty"`
+	Imports    []Import    `json:"imports,omitempty"`
+	Functions  []Function  `json:"functions,omitempty"`
+	Structs    []Struct    `json:"structs,omitempty"`
+	Interfaces []Interface `json:"interfaces,omitempty"`
+	Types      []Type      `json:"types,omitempty"`
+	Enums      []Enum      `json:"enums,omitempty"`
+	Constants  []Constant  `json:"constants,omitempty"`
+	Errors     []Error     `json:"errors,omitempty"`
+}
+
+//Import is an imported package, with its explicit name if it has one
+type Import struct {
+	Path string `json:"path"`
+	Name string `json:"name,omitempty"`
+}
+
+//Function is an exported function, or a method when Receiver is set.
+//Types are written as in Go source, qualified by the package name when
+//declared in another package.
+type Function struct {
+	Name       string      `json:"name"`
+	Doc        string      `json:"doc,omitempty"`
+	Receiver   string      `json:"receiver,omitempty"`
+	Params     []Param     `json:"params,omitempty"`
+	Results    []Param     `json:"results,omitempty"`
+	Directives *Directives `json:"directives,omitempty"`
+	Pos        *Pos        `json:"pos,omitempty"`
+}
+
+//Param is a parameter or result. Name can be blank.
+type Param struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
+
+//Struct is an exported struct type. Its fields are not bridged, so are not
+//recorded.
+type Struct struct {
+	Name       string      `json:"name"`
+	Doc        string      `json:"doc,omitempty"`
+	Directives *Directives `json:"directives,omitempty"`
+	Pos        *Pos        `json:"pos,omitempty"`
+}
+
+//Interface is an exported interface type, with its exported methods and
+//the interfaces it embeds
+type Interface struct {
+	Name       string      `json:"name"`
+	Doc        string      `json:"doc,omitempty"`
+	Methods    []Function  `json:"methods,omitempty"`
+	Embedded   []string    `json:"embedded,omitempty"`
+	Directives *Directives `json:"directives,omitempty"`
+	Pos        *Pos        `json:"pos,omitempty"`
+}
+
+//Type is a named type that is neither a struct, an interface nor an enum.
+//Underlying is set for types declared on a basic type.
+type Type struct {
+	Name       string      `json:"name"`
+	Doc        string      `json:"doc,omitempty"`
+	Underlying string      `json:"underlying,omitempty"`
+	Directives *Directives `json:"directives,omitempty"`
+	Pos        *Pos        `json:"pos,omitempty"`
+}
+
+//Enum is a named integer type and the constants declared of it
+type Enum struct {
+	Name       string      `json:"name"`
+	Doc        string      `json:"doc,omitempty"`
+	Underlying string      `json:"underlying"`
+	Values     []Constant  `json:"values"`
+	Directives *Directives `json:"directives,omitempty"`
+	Pos        *Pos        `json:"pos,omitempty"`
+}
+
+//Constant is an exported constant. Value is its exact Go representation.
+type Constant struct {
+	Name       string      `json:"name"`
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Directives *Directives `json:"directives,omitempty"`
+	Pos        *Pos        `json:"pos,omitempty"`
+}
+
+//Error is an error the functions of the package can return, see types.GoError
+type Error struct {
+	Name    string `json:"name,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+	Prefix  bool   `json:"prefix,omitempty"`
+	Typed   bool   `json:"typed,omitempty"`
+	Pos     *Pos   `json:"pos,omitempty"`
+}
+
+//Directives are the reactgonative directives declared on a symbol
+type Directives struct {
+	Ignore bool   `json:"ignore,omitempty"`
+	Sync   bool   `json:"sync,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Thread string `json:"thread,omitempty"`
+	Code   string `json:"code,omitempty"`
+}
+
+//Pos is the position a symbol is declared at, used to report diagnostics.
+//File is the name of the file within the package directory.
+type Pos struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column,omitempty"`
+}
+
+//Write writes pkgs to w as an indented IR document
+func Write(w io.Writer, pkgs []types.GoType) error {
+	f := File{Version: Version, Packages: make([]Package, 0, len(pkgs))}
+	for _, g := range pkgs {
+		f.Packages = append(f.Packages, FromGoType(g))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+//Load reads the IR document at path, returning its packages. folder, when
+//set, gives the directory of a package from its import path, and the
+//positions of the package are resolved against it.
+func Load(path string, folder func(importPath string) string) ([]types.GoType, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := File{}
+	err = json.Unmarshal(data, &f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	err = f.validate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	pkgs := make([]types.GoType, 0, len(f.Packages))
+	for _, p := range f.Packages {
+		dir := ""
+		if folder != nil && p.ImportPath != "" {
+			dir = folder(p.ImportPath)
+		}
+		pkgs = append(pkgs, p.GoType(dir))
+	}
+	return pkgs, nil
+}
+
+//validate checks what the generators rely on but JSON cannot express,
+//leaving whether each symbol can be bridged to the validator
+func (f File) validate() error {
+	if f.Version != Version {
+		return fmt.Errorf("unsupported IR version %d, expected %d", f.Version, Version)
+	}
+	seen := make(map[string]bool)
+	for i, p := range f.Packages {
+		if p.Name == "" {
+			return fmt.Errorf("package %d has no name", i)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("package %s appears more than once, which gomobile cannot bind", p.Name)
+		}
+		seen[p.Name] = true
+		for _, fn := range p.Functions {
+			if err := fn.validate(); err != nil {
+				return fmt.Errorf("%s: %s", p.Name, err.Error())
+			}
+		}
+		for _, iface := range p.Interfaces {
+			for _, m := range iface.Methods {
+				if err := m.validate(); err != nil {
+					return fmt.Errorf("%s.%s: %s", p.Name, iface.Name, err.Error())
+				}
+			}
+		}
+		for _, e := range p.Enums {
+			if e.Name == "" || e.Underlying == "" {
+				return fmt.Errorf("%s: enums need a name and an underlying type", p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (fn Function) validate() error {
+	if fn.Name == "" {
+		return fmt.Errorf("function has no name")
+	}
+	for _, p := range append(fn.Params, fn.Results...) {
+		if p.Type == "" {
+			return fmt.Errorf("%s: parameter %q has no type", fn.Name, p.Name)
+		}
+	}
+	if fn.Directives != nil && fn.Directives.Thread != "" && !types.IsThread(fn.Directives.Thread) {
+		return fmt.Errorf("%s: unknown thread %q, expected inline, background or pool", fn.Name, fn.Directives.Thread)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package ir
+
+import (
+	"bytes"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+func TestRoundTrip(t *testing.T) {
+	Convey("Given a parsed package using every kind of declaration", t, func() {
+		src := filepath.Join(t.TempDir(), "src", "example.com", "hello")
+		pos := token.Position{Filename: filepath.Join(src, "hello.go"), Line: 3, Column: 6}
+		g := types.GoType{
+			PackageName: "hello",
+			ImportPath:  "example.com/hello",
+			Imports:     map[string]string{"example.com/status": "", "errors": "errs"},
+			Functions: []types.GoFunction{
+				types.GoFunction{
+					Name:       "Greet",
+					Doc:        "Greet says hello.",
+					Params:     []types.GoParams{types.GoParams{Name: "name", T: "string"}},
+					Results:    []types.GoParams{types.GoParams{T: "error"}, types.GoParams{T: "string"}},
+					Directives: types.GoDirectives{Sync: true, Name: "hi"},
+					Pos:        pos,
+				},
+				types.GoFunction{Name: "Close", Receiver: "*Server", Params: []types.GoParams{}, Results: []types.GoParams{}},
+			},
+			Returns: []types.GoParams{types.GoParams{T: "string"}, types.GoParams{}},
+			Types: map[string]types.GoTypeSpec{
+				"Server": types.GoTypeSpec{Name: "Server", Kind: types.KindStruct, Directives: types.GoDirectives{Ignore: true}},
+				"Counter": types.GoTypeSpec{
+					Name:     "Counter",
+					Doc:      "Counter counts.",
+					Kind:     types.KindInterface,
+					Embedded: []string{"fmt.Stringer"},
+					Methods: []types.GoFunction{types.GoFunction{
+						Name:    "OnProgress",
+						Params:  []types.GoParams{types.GoParams{Name: "n", T: "int"}},
+						Results: []types.GoParams{},
+					}},
+				},
+				"Mode":   types.GoTypeSpec{Name: "Mode", Kind: types.KindBasic, Underlying: "string"},
+				"Hook":   types.GoTypeSpec{Name: "Hook", Kind: types.KindOther},
+				"Status": types.GoTypeSpec{Name: "Status", Kind: types.KindBasic, Underlying: "int", Pos: pos},
+			},
+			Constants: []types.GoConstant{types.GoConstant{Name: "Version", T: "string", Value: `"1.0"`}},
+			Enums: []types.GoEnum{types.GoEnum{
+				Name:       "Status",
+				Underlying: "int",
+				Values:     []types.GoConstant{types.GoConstant{Name: "StatusActive", T: "Status", Value: "0", Pos: pos}},
+				Pos:        pos,
+			}},
+			Errors: []types.GoError{
+				types.GoError{Name: "ErrNotFound", Code: "E_NOT_FOUND", Message: "not found", Pos: pos},
+				types.GoError{Name: "QuotaError", Code: "E_QUOTA", Message: "quota", Prefix: true, Typed: true},
+			},
+		}
+		Convey("When it is written and loaded back", func() {
+			var buf bytes.Buffer
+			So(Write(&buf, []types.GoType{g}), ShouldBeNil)
+			path := filepath.Join(t.TempDir(), "bridge.json")
+			So(ioutil.WriteFile(path, buf.Bytes(), 0644), ShouldBeNil)
+			folders := map[string]string{"example.com/hello": src}
+			pkgs, err := Load(path, func(importPath string) string { return folders[importPath] })
+			So(err, ShouldBeNil)
+			Convey("Then the package is unchanged", func() {
+				So(len(pkgs), ShouldEqual, 1)
+				So(pkgs[0], ShouldResemble, g)
+			})
+			Convey("And the document is versioned", func() {
+				So(buf.String(), ShouldStartWith, "{\n  \"version\": 1,")
+			})
+			Convey("And positions are recorded within the package directory", func() {
+				So(buf.String(), ShouldContainSubstring, `"file": "hello.go",`)
+				So(buf.String(), ShouldNotContainSubstring, src)
+			})
+		})
+		Convey("When it is loaded without the package directory", func() {
+			var buf bytes.Buffer
+			So(Write(&buf, []types.GoType{g}), ShouldBeNil)
+			path := filepath.Join(t.TempDir(), "bridge.json")
+			So(ioutil.WriteFile(path, buf.Bytes(), 0644), ShouldBeNil)
+			pkgs, err := Load(path, nil)
+			So(err, ShouldBeNil)
+			Convey("Then positions name the file alone", func() {
+				So(pkgs[0].Functions[0].Pos, ShouldResemble, token.Position{Filename: "hello.go", Line: 3, Column: 6})
+			})
+		})
+	})
+}
+
+func TestLoad(t *testing.T) {
+	Convey("Given IR documents another tool wrote", t, func() {
+		dir := t.TempDir()
+		write := func(content string) string {
+			path := filepath.Join(dir, "bridge.json")
+			So(ioutil.WriteFile(path, []byte(content), 0644), ShouldBeNil)
+			return path
+		}
+		Convey("When one is loaded", func() {
+			pkgs, err := Load(write(`{"version": 1, "packages": [{"name": "hello",
+				"functions": [{"name": "Greet", "params": [{"name": "name", "type": "string"}],
+					"results": [{"type": "string"}, {"type": "error"}]}]}]}`), nil)
+			Convey("Then the return types are derived from the results", func() {
+				So(err, ShouldBeNil)
+				So(pkgs[0].Returns, ShouldResemble, []types.GoParams{types.GoParams{T: "string"}})
+				So(pkgs[0].IsValid(), ShouldBeTrue)
+			})
+		})
+		cases := map[string]string{
+			"another version":    `{"version": 2, "packages": []}`,
+			"an unnamed package": `{"version": 1, "packages": [{"functions": []}]}`,
+			"a repeated package": `{"version": 1, "packages": [{"name": "a"}, {"name": "a"}]}`,
+			"an untyped param":   `{"version": 1, "packages": [{"name": "a", "functions": [{"name": "F", "params": [{"name": "x"}]}]}]}`,
+			"an unknown thread":  `{"version": 1, "packages": [{"name": "a", "functions": [{"name": "F", "directives": {"thread": "ui"}}]}]}`,
+			"malformed json":     `{"version": 1,`,
+		}
+		for name, content := range cases {
+			Convey("Then "+name+" is an error", func() {
+				_, err := Load(write(content), nil)
+				So(err, ShouldNotBeNil)
+			})
+		}
+		Convey("Then a missing file is an error", func() {
+			_, err := Load(filepath.Join(dir, "missing.json"), nil)
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,134 @@
+package javasyntax
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const module = `package com.test.bridge.hello;
+
+import com.facebook.react.bridge.Promise;
+import java.util.*;
+import hello.Hello;
+
+public class HelloModule extends ReactContextBaseJavaModule {
+
+	private final ExecutorService executor = Executors.newSingleThreadExecutor();
+	private static final long[] LIMITS = new long[]{1L, 2L};
+
+	public HelloModule(ReactApplicationContext reactContext) {
+		super(reactContext);
+	}
+
+	@Override
+	public Map<String, Object> getConstants() {
+		final Map<String, Object> constants = new HashMap<>();
+		constants.put("Ratio", 1.5e3);
+		return constants;
+	}
+
+	@ReactMethod(isBlockingSynchronousMethod = true)
+	public String version() {
+		return Hello.version();
+	}
+
+	@ReactMethod
+	public void greetings(String name, Promise promise) {
+		executor.execute(() -> {
+			try {
+				String returnParam1 = Hello.greetings(name);
+				promise.resolve(returnParam1);
+			} catch(Exception e) {
+				rejectGoError(promise, e);
+			}
+		});
+	}
+
+	public static String statusToString(long value) {
+		if (value == 0L) {
+			return "Active";
+		} else if (value > 1 && !(value <= 4)) {
+			return "Other\t\"quoted\"\u00e9";
+		}
+		for (int i = 0; i < LIMITS.length; i++) {
+			value += (long) i * 2;
+		}
+		for (long limit : LIMITS) {
+			value = limit > 0 ? limit : -limit;
+		}
+		return null;
+	}
+
+	private static void rejectGoError(Promise promise, Exception e) {
+		final String message = e.getMessage() == null ? "" : e.getMessage();
+		if (message.startsWith("quota") || e instanceof IllegalStateException) {
+			final WritableMap userInfo = Arguments.createMap();
+			promise.reject("E_QUOTA", message, e, userInfo);
+			return;
+		}
+		throw new IllegalArgumentException("Unknown: " + (String) message);
+	}
+
+	@Override
+	public List <Class<? extends JavaScriptModule>> createJSModules() {
+		context.getJSModule(DeviceEventManagerModule.RCTDeviceEventEmitter.class).emit("a", null);
+		return Collections.emptyList();
+	}
+}
+`
+
+func TestCheck(t *testing.T) {
+	Convey("Given Java of the kind the bridge generates", t, func() {
+		Convey("When it is checked", func() {
+			err := Check([]byte(module))
+			Convey("Then there is no error", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a return type in a method parameter list", t, func() {
+		src := "class A {\n\tpublic void greetings(String , Promise promise) {\n\t}\n}\n"
+		Convey("When it is checked", func() {
+			err := Check([]byte(src))
+			Convey("Then the error is positioned at the missing name", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `2:31: expected identifier, found ","`)
+			})
+		})
+	})
+	Convey("Given malformed Java", t, func() {
+		cases := map[string]string{
+			"missing semicolon":    "class A { void a() { int x = 1 } }",
+			"unbalanced braces":    "class A { void a() { if (x) { } }",
+			"empty parameter":      "class A { void a( , Promise p) {} }",
+			"dangling operator":    "class A { void a() { x = 1 + ; } }",
+			"try without catch":    "class A { void a() { try { } } }",
+			"unterminated string":  "class A { String s = \"abc; }",
+			"unknown escape":       "class A { String s = \"\\q\"; }",
+			"unknown character":    "class A { int x = #1; }",
+			"statement in a class": "class A { return; }",
+			"missing package name": "package ;",
+		}
+		for name, src := range cases {
+			Convey("Then "+name+" is an error", func() {
+				err := Check([]byte(src))
+				So(err, ShouldNotBeNil)
+				_, ok := err.(*Error)
+				So(ok, ShouldBeTrue)
+			})
+		}
+	})
+}
+
+func FuzzCheck(f *testing.F) {
+	f.Add(module)
+	f.Add("class A { void a() { int x = 1 } }")
+	f.Add("class A { String s = \"\\q\"; }")
+	f.Fuzz(func(t *testing.T, src string) {
+		err := Check([]byte(src))
+		if _, ok := err.(*Error); err != nil && !ok {
+			t.Fatalf("unexpected error type %T: %v", err, err)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package javasyntax
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Kinds of token
+const (
+	kindEOF = iota
+	kindIdent
+	kindKeyword
+	kindNumber
+	kindString
+	kindChar
+	kindOperator
+)
+
+//token is a single lexical token, positioned by 1 based line and byte column
+type token struct {
+	kind int
+	text string
+	line int
+	col  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case kindEOF:
+		return "end of file"
+	case kindString, kindChar, kindNumber:
+		return "literal " + t.text
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+var keywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true,
+	"case": true, "catch": true, "char": true, "class": true, "const": true,
+	"continue": true, "default": true, "do": true, "double": true, "else": true,
+	"enum": true, "extends": true, "false": true, "final": true, "finally": true,
+	"float": true, "for": true, "goto": true, "if": true, "implements": true,
+	"import": true, "instanceof": true, "int": true, "interface": true, "long": true,
+	"native": true, "new": true, "null": true, "package": true, "private": true,
+	"protected": true, "public": true, "return": true, "short": true, "static": true,
+	"strictfp": true, "super": true, "switch": true, "synchronized": true, "this": true,
+	"throw": true, "throws": true, "transient": true, "true": true, "try": true,
+	"void": true, "volatile": true, "while": true,
+}
+
+//operators are matched longest first. > is never combined, so closing
+//nested type arguments needs no special case.
+var operators = []string{
+	"<<=", "...", "->", "::", "++", "--", "&&", "||", "==", "!=", "<=", ">=",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "<<",
+	"(", ")", "{", "}", "[", "]", ";", ",", ".", "@", "=", ">", "<", "!",
+	"~", "?", ":", "+", "-", "*", "/", "&", "|", "^", "%",
+}
+
+//lex splits src into tokens, ending with an EOF token, skipping whitespace
+//and comments
+func lex(src string) ([]token, error) {
+	tokens := make([]token, 0, len(src)/4)
+	line, col := 1, 1
+	advance := func(n int) {
+		for _, r := range src[:n] {
+			if r == '\n' {
+				line++
+				col = 1
+			} else {
+				col += len(string(r))
+			}
+		}
+		src = src[n:]
+	}
+	for len(src) > 0 {
+		c := src[0]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			advance(1)
+		case strings.HasPrefix(src, "//"):
+			end := strings.IndexByte(src, '\n')
+			if end < 0 {
+				end = len(src)
+			}
+			advance(end)
+		case strings.HasPrefix(src, "/*"):
+			end := strings.Index(src[2:], "*/")
+			if end < 0 {
+				return nil, &Error{Line: line, Col: col, Msg: "comment not terminated"}
+			}
+			advance(end + 4)
+		case isLetter(c):
+			n := 1
+			for n < len(src) && (isLetter(src[n]) || isDigit(src[n])) {
+				n++
+			}
+			kind := kindIdent
+			if keywords[src[:n]] {
+				kind = kindKeyword
+			}
+			tokens = append(tokens, token{kind: kind, text: src[:n], line: line, col: col})
+			advance(n)
+		case isDigit(c) || (c == '.' && len(src) > 1 && isDigit(src[1])):
+			n := 1
+			for n < len(src) && (isDigit(src[n]) || isLetter(src[n]) || src[n] == '.' ||
+				((src[n] == '+' || src[n] == '-') && (src[n-1] == 'e' || src[n-1] == 'E'))) {
+				n++
+			}
+			tokens = append(tokens, token{kind: kindNumber, text: src[:n], line: line, col: col})
+			advance(n)
+		case c == '"' || c == '\'':
+			n, err := quoted(src)
+			if err != "" {
+				return nil, &Error{Line: line, Col: col, Msg: err}
+			}
+			kind := kindString
+			if c == '\'' {
+				kind = kindChar
+			}
+			tokens = append(tokens, token{kind: kind, text: src[:n], line: line, col: col})
+			advance(n)
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src, o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, &Error{Line: line, Col: col, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+			tokens = append(tokens, token{kind: kindOperator, text: op, line: line, col: col})
+			advance(len(op))
+		}
+	}
+	return append(tokens, token{kind: kindEOF, line: line, col: col}), nil
+}
+
+//quoted returns the length of the string or char literal at the start of
+//src, or a message describing why it is malformed
+func quoted(src string) (int, string) {
+	quote := src[0]
+	for n := 1; n < len(src); n++ {
+		switch src[n] {
+		case '\\':
+			if n+1 >= len(src) {
+				return 0, "literal not terminated"
+			}
+			if !strings.ContainsRune(`btnfrsu"'\01234567`, rune(src[n+1])) {
+				return 0, fmt.Sprintf("unknown escape \\%c", src[n+1])
+			}
+			n++
+		case '\n':
+			return 0, "literal not terminated"
+		case quote:
+			return n + 1, ""
+		}
+	}
+	return 0, "literal not terminated"
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
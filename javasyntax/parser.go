@@ -0,0 +1,878 @@
+//Package javasyntax checks the syntax of the Java the bridge generates,
+//so malformed output is caught without a JDK.
+//It parses the subset of Java used by generated code: classes and
+//interfaces with fields, constructors and methods, annotations, generics,
+//lambdas and the common statements and expressions. It does not resolve
+//names or types.
+package javasyntax
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+//Error is the first syntax error found in a Java file
+type Error struct {
+	File string
+	Line int
+	Col  int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Col, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Col, e.Msg)
+}
+
+//Check parses src as a Java compilation unit, returning an *Error for the
+//first syntax error
+func Check(src []byte) (err error) {
+	tokens, err := lex(string(src))
+	if err != nil {
+		return err
+	}
+	p := &parser{tokens: tokens}
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*Error)
+			if !ok {
+				panic(r)
+			}
+			err = e
+		}
+	}()
+	p.compilationUnit()
+	return nil
+}
+
+//CheckFile checks the Java file at path, naming it in any *Error
+func CheckFile(path string) error {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	err = Check(src)
+	if e, ok := err.(*Error); ok {
+		e.File = path
+	}
+	return err
+}
+
+var modifiers = map[string]bool{
+	"public": true, "protected": true, "private": true, "static": true, "final": true,
+	"abstract": true, "native": true, "synchronized": true, "transient": true,
+	"volatile": true, "strictfp": true, "default": true,
+}
+
+var primitives = map[string]bool{
+	"boolean": true, "byte": true, "char": true, "short": true, "int": true,
+	"long": true, "float": true, "double": true,
+}
+
+var assignments = map[string]bool{
+	"=": true, "+=": true, "-=": true, "*=": true, "/=": true, "%=": true,
+	"&=": true, "|=": true, "^=": true, "<<=": true,
+}
+
+//binaryPrecedence orders the binary operators, loosest first
+var binaryPrecedence = map[string]int{
+	"||": 1, "&&": 2, "|": 3, "^": 4, "&": 5, "==": 6, "!=": 6,
+	"<": 7, ">": 7, "<=": 7, ">=": 7, "instanceof": 7,
+	"<<": 8, "+": 9, "-": 9, "*": 10, "/": 10, "%": 10,
+}
+
+//parser is a backtracking recursive descent parser. Errors panic with an
+//*Error, recovered by Check or by a speculative parse.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekN(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != kindEOF {
+		p.pos++
+	}
+	return t
+}
+
+//is identifies whether the next token is text, and not a literal
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return t.text == text && (t.kind == kindOperator || t.kind == kindKeyword)
+}
+
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) {
+	if !p.accept(text) {
+		p.fail("expected %q", text)
+	}
+}
+
+func (p *parser) ident() string {
+	t := p.peek()
+	if t.kind != kindIdent {
+		p.fail("expected identifier")
+	}
+	p.pos++
+	return t.text
+}
+
+func (p *parser) fail(format string, args ...interface{}) {
+	t := p.peek()
+	panic(&Error{Line: t.line, Col: t.col, Msg: fmt.Sprintf(format, args...) + ", found " + t.String()})
+}
+
+//try runs parse, restoring the position and reporting false if it fails
+func (p *parser) try(parse func()) (ok bool) {
+	start := p.pos
+	defer func() {
+		if r := recover(); r != nil {
+			if _, isError := r.(*Error); !isError {
+				panic(r)
+			}
+			p.pos = start
+			ok = false
+		}
+	}()
+	parse()
+	return true
+}
+
+func (p *parser) compilationUnit() {
+	p.annotations()
+	if p.accept("package") {
+		p.qualifiedName()
+		p.expect(";")
+	}
+	for p.accept("import") {
+		p.accept("static")
+		p.ident()
+		for p.accept(".") {
+			if p.accept("*") {
+				break
+			}
+			p.ident()
+		}
+		p.expect(";")
+	}
+	for p.peek().kind != kindEOF {
+		if p.accept(";") {
+			continue
+		}
+		p.modifiers()
+		p.typeDeclaration()
+	}
+}
+
+func (p *parser) qualifiedName() {
+	p.ident()
+	for p.accept(".") {
+		p.ident()
+	}
+}
+
+func (p *parser) annotations() {
+	for p.is("@") && !(p.peekN(1).text == "interface") {
+		p.annotation()
+	}
+}
+
+func (p *parser) annotation() {
+	p.expect("@")
+	p.qualifiedName()
+	if !p.accept("(") {
+		return
+	}
+	if p.accept(")") {
+		return
+	}
+	if p.peek().kind == kindIdent && p.peekN(1).text == "=" {
+		for {
+			p.ident()
+			p.expect("=")
+			p.elementValue()
+			if !p.accept(",") {
+				break
+			}
+		}
+	} else {
+		p.elementValue()
+	}
+	p.expect(")")
+}
+
+func (p *parser) elementValue() {
+	switch {
+	case p.is("@"):
+		p.annotation()
+	case p.accept("{"):
+		for !p.accept("}") {
+			p.elementValue()
+			if !p.accept(",") {
+				p.expect("}")
+				return
+			}
+		}
+	default:
+		p.conditional()
+	}
+}
+
+func (p *parser) modifiers() {
+	for {
+		switch {
+		case p.is("@") && p.peekN(1).text != "interface":
+			p.annotation()
+		case p.peek().kind == kindKeyword && modifiers[p.peek().text]:
+			p.next()
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) typeDeclaration() {
+	switch {
+	case p.accept("class"):
+		p.ident()
+		p.typeParameters()
+		if p.accept("extends") {
+			p.typ()
+		}
+		if p.accept("implements") {
+			p.typeList()
+		}
+	case p.accept("interface"):
+		p.ident()
+		p.typeParameters()
+		if p.accept("extends") {
+			p.typeList()
+		}
+	default:
+		p.fail("expected class or interface")
+	}
+	p.classBody()
+}
+
+func (p *parser) typeList() {
+	p.typ()
+	for p.accept(",") {
+		p.typ()
+	}
+}
+
+func (p *parser) typeParameters() {
+	if !p.accept("<") {
+		return
+	}
+	for {
+		p.ident()
+		if p.accept("extends") {
+			p.typ()
+			for p.accept("&") {
+				p.typ()
+			}
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect(">")
+}
+
+func (p *parser) classBody() {
+	p.expect("{")
+	for !p.accept("}") {
+		if p.peek().kind == kindEOF {
+			p.fail("expected \"}\"")
+		}
+		p.member()
+	}
+}
+
+func (p *parser) member() {
+	if p.accept(";") {
+		return
+	}
+	if p.is("{") || (p.is("static") && p.peekN(1).text == "{") {
+		p.accept("static")
+		p.block()
+		return
+	}
+	p.modifiers()
+	if p.is("class") || p.is("interface") {
+		p.typeDeclaration()
+		return
+	}
+	p.typeParameters()
+	if p.peek().kind == kindIdent && p.peekN(1).text == "(" {
+		//Constructor
+		p.ident()
+		p.methodRest()
+		return
+	}
+	if !p.accept("void") {
+		p.typ()
+	}
+	p.ident()
+	if p.is("(") {
+		p.methodRest()
+		return
+	}
+	p.variableDeclaratorsRest()
+	p.expect(";")
+}
+
+func (p *parser) methodRest() {
+	p.formalParameters()
+	p.dims()
+	if p.accept("throws") {
+		p.typeList()
+	}
+	if p.accept(";") {
+		return
+	}
+	p.block()
+}
+
+func (p *parser) formalParameters() {
+	p.expect("(")
+	if p.accept(")") {
+		return
+	}
+	for {
+		p.modifiers()
+		p.typ()
+		p.accept("...")
+		p.ident()
+		p.dims()
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect(")")
+}
+
+func (p *parser) dims() {
+	for p.is("[") && p.peekN(1).text == "]" {
+		p.next()
+		p.next()
+	}
+}
+
+//variableDeclaratorsRest parses the rest of a declaration following the
+//first declared name
+func (p *parser) variableDeclaratorsRest() {
+	for {
+		p.dims()
+		if p.accept("=") {
+			p.variableInitializer()
+		}
+		if !p.accept(",") {
+			return
+		}
+		p.ident()
+	}
+}
+
+func (p *parser) variableInitializer() {
+	if p.is("{") {
+		p.arrayInitializer()
+		return
+	}
+	p.expression()
+}
+
+func (p *parser) arrayInitializer() {
+	p.expect("{")
+	for !p.accept("}") {
+		p.variableInitializer()
+		if !p.accept(",") {
+			p.expect("}")
+			return
+		}
+	}
+}
+
+func (p *parser) typ() {
+	t := p.peek()
+	if t.kind == kindKeyword && primitives[t.text] {
+		p.next()
+		p.dims()
+		return
+	}
+	p.ident()
+	p.typeArguments()
+	for p.is(".") && p.peekN(1).kind == kindIdent {
+		p.next()
+		p.ident()
+		p.typeArguments()
+	}
+	p.dims()
+}
+
+func (p *parser) typeArguments() {
+	if !p.accept("<") {
+		return
+	}
+	if p.accept(">") {
+		//Diamond
+		return
+	}
+	for {
+		if p.accept("?") {
+			if p.accept("extends") || p.accept("super") {
+				p.typ()
+			}
+		} else {
+			p.typ()
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect(">")
+}
+
+func (p *parser) block() {
+	p.expect("{")
+	for !p.accept("}") {
+		if p.peek().kind == kindEOF {
+			p.fail("expected \"}\"")
+		}
+		p.blockStatement()
+	}
+}
+
+func (p *parser) blockStatement() {
+	if p.is("class") || p.is("interface") || p.is("abstract") {
+		p.modifiers()
+		p.typeDeclaration()
+		return
+	}
+	if p.try(p.localVariableDeclaration) {
+		p.expect(";")
+		return
+	}
+	p.statement()
+}
+
+//localVariableDeclaration parses a declaration up to its semicolon. It fails
+//without a declared name following the type, so expressions are tried next.
+func (p *parser) localVariableDeclaration() {
+	p.modifiers()
+	p.typ()
+	p.ident()
+	if !p.is("=") && !p.is(";") && !p.is(",") && !p.is("[") && !p.is(":") {
+		p.fail("expected declaration")
+	}
+	p.variableDeclaratorsRest()
+}
+
+func (p *parser) statement() {
+	t := p.peek()
+	if t.kind == kindIdent && p.peekN(1).text == ":" {
+		p.next()
+		p.next()
+		p.statement()
+		return
+	}
+	switch {
+	case p.is("{"):
+		p.block()
+	case p.accept(";"):
+	case p.accept("if"):
+		p.parExpression()
+		p.statement()
+		if p.accept("else") {
+			p.statement()
+		}
+	case p.accept("while"):
+		p.parExpression()
+		p.statement()
+	case p.accept("do"):
+		p.statement()
+		p.expect("while")
+		p.parExpression()
+		p.expect(";")
+	case p.accept("for"):
+		p.forControl()
+		p.statement()
+	case p.accept("return"):
+		if !p.accept(";") {
+			p.expression()
+			p.expect(";")
+		}
+	case p.accept("throw"):
+		p.expression()
+		p.expect(";")
+	case p.accept("break"), p.accept("continue"):
+		if p.peek().kind == kindIdent {
+			p.next()
+		}
+		p.expect(";")
+	case p.accept("synchronized"):
+		p.parExpression()
+		p.block()
+	case p.accept("try"):
+		p.tryRest()
+	case p.accept("switch"):
+		p.switchRest()
+	default:
+		p.expression()
+		p.expect(";")
+	}
+}
+
+func (p *parser) parExpression() {
+	p.expect("(")
+	p.expression()
+	p.expect(")")
+}
+
+func (p *parser) forControl() {
+	p.expect("(")
+	enhanced := false
+	if !p.is(";") {
+		if p.try(p.localVariableDeclaration) {
+			enhanced = p.accept(":")
+		} else {
+			p.expressionList()
+		}
+	}
+	if enhanced {
+		p.expression()
+		p.expect(")")
+		return
+	}
+	p.expect(";")
+	if !p.is(";") {
+		p.expression()
+	}
+	p.expect(";")
+	if !p.is(")") {
+		p.expressionList()
+	}
+	p.expect(")")
+}
+
+func (p *parser) expressionList() {
+	p.expression()
+	for p.accept(",") {
+		p.expression()
+	}
+}
+
+func (p *parser) tryRest() {
+	if p.accept("(") {
+		for !p.accept(")") {
+			p.modifiers()
+			p.typ()
+			p.ident()
+			p.expect("=")
+			p.expression()
+			if !p.accept(";") {
+				p.expect(")")
+				break
+			}
+		}
+	}
+	p.block()
+	caught := false
+	for p.accept("catch") {
+		caught = true
+		p.expect("(")
+		p.modifiers()
+		p.typ()
+		for p.accept("|") {
+			p.typ()
+		}
+		p.ident()
+		p.expect(")")
+		p.block()
+	}
+	if p.accept("finally") {
+		p.block()
+	} else if !caught {
+		p.fail("expected catch or finally")
+	}
+}
+
+func (p *parser) switchRest() {
+	p.parExpression()
+	p.expect("{")
+	for !p.accept("}") {
+		switch {
+		case p.accept("case"):
+			p.conditional()
+			p.expect(":")
+		case p.accept("default"):
+			p.expect(":")
+		default:
+			if p.peek().kind == kindEOF {
+				p.fail("expected \"}\"")
+			}
+			p.blockStatement()
+		}
+	}
+}
+
+func (p *parser) expression() {
+	if p.isLambda() {
+		p.lambda()
+		return
+	}
+	p.conditional()
+	t := p.peek()
+	if t.kind == kindOperator && assignments[t.text] {
+		p.next()
+		p.expression()
+	}
+}
+
+//isLambda identifies whether a lambda starts at the next token, as an
+//identifier or parenthesised parameters followed by ->
+func (p *parser) isLambda() bool {
+	if p.peek().kind == kindIdent {
+		return p.peekN(1).text == "->"
+	}
+	if !p.is("(") {
+		return false
+	}
+	depth := 0
+	for n := 0; ; n++ {
+		t := p.peekN(n)
+		switch {
+		case t.kind == kindEOF:
+			return false
+		case t.text == "(" && t.kind == kindOperator:
+			depth++
+		case t.text == ")" && t.kind == kindOperator:
+			depth--
+			if depth == 0 {
+				return p.peekN(n+1).text == "->"
+			}
+		}
+	}
+}
+
+func (p *parser) lambda() {
+	if p.peek().kind == kindIdent {
+		p.ident()
+	} else {
+		p.expect("(")
+		for !p.accept(")") {
+			if p.peek().kind == kindIdent && (p.peekN(1).text == "," || p.peekN(1).text == ")") {
+				p.ident()
+			} else {
+				p.modifiers()
+				p.typ()
+				p.ident()
+			}
+			if !p.accept(",") {
+				p.expect(")")
+				break
+			}
+		}
+	}
+	p.expect("->")
+	if p.is("{") {
+		p.block()
+		return
+	}
+	p.expression()
+}
+
+func (p *parser) conditional() {
+	p.binary(1)
+	if p.accept("?") {
+		p.expression()
+		p.expect(":")
+		if p.isLambda() {
+			p.lambda()
+			return
+		}
+		p.conditional()
+	}
+}
+
+//binary parses operators of at least the given precedence
+func (p *parser) binary(precedence int) {
+	p.unary()
+	for {
+		t := p.peek()
+		level, ok := binaryPrecedence[t.text]
+		if !ok || t.kind == kindString || t.kind == kindChar || level < precedence {
+			return
+		}
+		p.next()
+		if t.text == "instanceof" {
+			p.modifiers()
+			p.typ()
+			continue
+		}
+		p.binary(level + 1)
+	}
+}
+
+func (p *parser) unary() {
+	switch {
+	case p.accept("++"), p.accept("--"), p.accept("+"), p.accept("-"), p.accept("!"), p.accept("~"):
+		p.unary()
+		return
+	case p.is("(") && p.try(p.cast):
+		return
+	}
+	p.primary()
+	p.selectors()
+	for p.accept("++") || p.accept("--") {
+	}
+}
+
+//cast parses a parenthesised type followed by the expression cast
+func (p *parser) cast() {
+	p.expect("(")
+	primitive := primitives[p.peek().text]
+	p.typ()
+	p.expect(")")
+	t := p.peek()
+	if !primitive && (t.kind == kindOperator && t.text != "(" && t.text != "!" && t.text != "~") {
+		p.fail("expected expression")
+	}
+	if t.kind == kindEOF || (t.kind == kindKeyword && binaryPrecedence[t.text] > 0) {
+		p.fail("expected expression")
+	}
+	if p.isLambda() {
+		p.lambda()
+		return
+	}
+	p.unary()
+}
+
+func (p *parser) primary() {
+	t := p.peek()
+	switch t.kind {
+	case kindNumber, kindString, kindChar:
+		p.next()
+		return
+	case kindIdent:
+		p.next()
+		if p.is("(") {
+			p.arguments()
+		}
+		return
+	}
+	switch {
+	case p.accept("true"), p.accept("false"), p.accept("null"):
+	case p.accept("this"), p.accept("super"):
+		if p.is("(") {
+			p.arguments()
+		}
+	case p.accept("new"):
+		p.creator()
+	case p.accept("("):
+		p.expression()
+		p.expect(")")
+	case t.kind == kindKeyword && (primitives[t.text] || t.text == "void"):
+		p.next()
+		p.dims()
+		p.expect(".")
+		p.expect("class")
+	default:
+		p.fail("expected expression")
+	}
+}
+
+func (p *parser) selectors() {
+	for {
+		switch {
+		case p.accept("."):
+			switch {
+			case p.accept("class"), p.accept("this"):
+			case p.accept("new"):
+				p.creator()
+			case p.is("<"):
+				p.typeArguments()
+				p.ident()
+				p.arguments()
+			default:
+				p.ident()
+				if p.is("(") {
+					p.arguments()
+				}
+			}
+		case p.accept("::"):
+			if !p.accept("new") {
+				p.ident()
+			}
+		case p.is("["):
+			p.next()
+			p.expression()
+			p.expect("]")
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) arguments() {
+	p.expect("(")
+	if p.accept(")") {
+		return
+	}
+	p.expressionList()
+	p.expect(")")
+}
+
+func (p *parser) creator() {
+	t := p.peek()
+	if t.kind == kindKeyword && primitives[t.text] {
+		p.next()
+	} else {
+		p.ident()
+		p.typeArguments()
+		for p.accept(".") {
+			p.ident()
+			p.typeArguments()
+		}
+	}
+	if p.is("[") {
+		dims := false
+		for p.accept("[") {
+			if !p.accept("]") {
+				p.expression()
+				p.expect("]")
+				dims = true
+			}
+		}
+		if !dims {
+			p.arrayInitializer()
+		}
+		return
+	}
+	p.arguments()
+	if p.is("{") {
+		p.classBody()
+	}
+}
@@ -1,65 +1,910 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"flag"
 	"fmt"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/steve-winter/reactgonative/api"
+	"github.com/steve-winter/reactgonative/binder"
+	"github.com/steve-winter/reactgonative/cache"
+	"github.com/steve-winter/reactgonative/config"
+	"github.com/steve-winter/reactgonative/diagnostics"
 	"github.com/steve-winter/reactgonative/filebuilder"
 	"github.com/steve-winter/reactgonative/goparser"
+	"github.com/steve-winter/reactgonative/ir"
+	"github.com/steve-winter/reactgonative/javasyntax"
+	"github.com/steve-winter/reactgonative/registration"
+	"github.com/steve-winter/reactgonative/report"
 	"github.com/steve-winter/reactgonative/types"
+	"github.com/steve-winter/reactgonative/validator"
+	"github.com/steve-winter/reactgonative/watcher"
 )
 
 var defaultAndroidRoot = "app/src/main/java/"
+var defaultAppRoot = "app/src/main/"
+var defaultAppModule = "app"
 var defaultPackageRoot = "com.reactgohybrid"
+var defaultJSRoot = "bridge/"
 var defaultGoPackage = "/golang.org/x/mobile/example/bind/hello"
 
 func main() {
-	fmt.Printf("Processing package %s\n", defaultGoPackage)
-	tList, err := goparser.Parsing(defaultGoPackage)
-	if err != nil {
-		fmt.Printf("Unable to parse file - %s\n", err.Error())
-	}
-	for _, t := range tList {
-		if t.IsValid() {
-			fmt.Printf("\tPackagename created: %s\n", t.PackageName)
-			typeString := module(t)
-			err := packageBuild(typeString, t.PackageName)
-			if err != nil {
-				fmt.Printf("Unable to build package - %s\n", err.Error())
+	c, err := config.Parse(os.Args[1:], os.Stderr)
+	if err == flag.ErrHelp {
+		os.Exit(exitOK)
+	}
+	if err != nil {
+		os.Exit(exitUsage)
+	}
+	//Progress goes to stderr when stdout carries json, either the report,
+	//the diagnostics or the IR
+	out := io.Writer(os.Stdout)
+	diagOut := io.Writer(os.Stderr)
+	switch {
+	case c.Command == config.CommandDumpIR || c.Report != "":
+		out = os.Stderr
+	case c.Format == diagnostics.FormatJSON:
+		out, diagOut = os.Stderr, os.Stdout
+	}
+	diags := &diagnostics.List{}
+	sum := summary{}
+	switch {
+	case c.Command == config.CommandDumpIR:
+		err = dumpIR(c, os.Stdout, out, diags)
+	case c.Command == config.CommandWatch:
+		stop := make(chan struct{})
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		go func() {
+			<-interrupt
+			close(stop)
+		}()
+		err = watch(c, stop, out, diagOut)
+		if err != nil {
+			fmt.Fprintf(diagOut, "Unable to watch packages - %s\n", err.Error())
+			os.Exit(exitParse)
+		}
+		return
+	case c.Unregister:
+		unregister(out, diags)
+	default:
+		sum, err = run(c, out, diags)
+	}
+	werr := diags.Write(diagOut, c.Format)
+	if werr != nil {
+		fmt.Fprintf(os.Stderr, "Unable to write diagnostics - %s\n", werr.Error())
+	}
+	if c.Command == config.CommandGenerate && !c.Unregister {
+		fmt.Fprintln(out, conclude(sum, err, diags))
+	}
+	if c.Report == config.ReportJSON {
+		werr = newReport(sum, err, diags).Write(os.Stdout)
+		if werr != nil {
+			fmt.Fprintf(os.Stderr, "Unable to write report - %s\n", werr.Error())
+		}
+	}
+	code := exitCode(err, diags, c.Strict)
+	if code != exitOK {
+		os.Exit(code)
+	}
+}
+
+//summary is what a run generated, for watch to report what changed and
+//for the report
+type summary struct {
+	//regenerated holds the packages written, rather than skipped as
+	//unchanged, in order
+	regenerated []string
+	modules     []api.Module
+	//packages describes every package validated, in order, and files the
+	//files shared by them
+	packages []report.Package
+	files    []report.File
+}
+
+//run generates the bridge for the configured packages, then integrates it
+//with the app as configured. A failed stage stops the run with an *Error,
+//skipping the stages depending on it. Failures of the API check and of
+//the integration are reported as diagnostics only.
+func run(c config.Config, out io.Writer, diags *diagnostics.List) (summary, error) {
+	return regenerate(c, nil, out, diags)
+}
+
+//regenerate runs like run, but when changed is not nil only writes the
+//packages with an import path in changed and those using their types. The
+//others are kept as the last run wrote them, so watch does not depend on
+//the cache.
+func regenerate(c config.Config, changed map[string]bool, out io.Writer, diags *diagnostics.List) (summary, error) {
+	sum := summary{}
+	parsed := &diagnostics.List{}
+	tList, paths := load(c, out, parsed)
+	diags.Merge(parsed)
+	err := stageFailed(StageParse, parsed, c.Strict)
+	if err != nil {
+		return sum, err
+	}
+	validated := &diagnostics.List{}
+	for i := range tList {
+		c.Bridge.Apply(&tList[i], validated)
+	}
+	//Types used across packages are validated in the package declaring them
+	types.Link(tList)
+	valid, issues := validate(tList, c.Jobs, validated)
+	types.Link(valid)
+	sum.packages = describe(valid, issues)
+	diags.Merge(validated)
+	err = stageFailed(StageValidate, validated, c.Strict)
+	if err != nil {
+		return sum, err
+	}
+	written := &diagnostics.List{}
+	previous, next, tool := loadCache(c.Cache, written)
+	results := make([]generated, len(valid))
+	forEach(c.Jobs, len(valid), func(i int) {
+		if valid[i].IsValid() {
+			kept := changed != nil && !affected(&valid[i], changed)
+			results[i] = bridgePackage(&valid[i], c.Aggregate, previous, tool, kept)
+		}
+	})
+	pooled := false
+	packages := make([]registration.Package, 0)
+	bridged := make([]string, 0)
+	refs := make([]filebuilder.Reference, 0)
+	for i := range valid {
+		t, r := &valid[i], &results[i]
+		if !t.IsValid() {
+			continue
+		}
+		fmt.Fprint(out, r.progress)
+		written.Merge(r.diags)
+		bridged = append(bridged, t.PackageName)
+		refs = append(refs, filebuilder.References(t)...)
+		sum.modules = append(sum.modules, filebuilder.APIModule(t))
+		pooled = pooled || t.UsesThread(types.ThreadPool)
+		if r.class != "" {
+			packages = append(packages, registration.Package{Class: r.class})
+		}
+		if r.cached {
+			next.Record(t.PackageName, r.entry)
+		}
+		if !r.fresh {
+			sum.regenerated = append(sum.regenerated, t.PackageName)
+		}
+		p := &sum.packages[i]
+		p.Status = report.StatusGenerated
+		switch {
+		case r.failed:
+			p.Status = report.StatusFailed
+		case r.fresh:
+			p.Status = report.StatusUnchanged
+		}
+		p.Files = reportFiles(r.outputs)
+	}
+	if pooled {
+		file, err := executorBuild(c.Bridge.PoolSize)
+		if err != nil {
+			writeFailed(written, token.Position{}, "executors", err)
+		} else {
+			sum.files = append(sum.files, reportFiles([]filebuilder.Output{file})...)
+		}
+	}
+	//The aggregate package registers every module, so it is only written
+	//once they all are
+	if c.Aggregate && len(bridged) > 0 && written.Count(diagnostics.Error) == 0 {
+		class, file, err := aggregateBuild(bridged)
+		if err != nil {
+			writeFailed(written, token.Position{}, "aggregate package", err)
+		} else {
+			packages = append(packages, registration.Package{Class: class})
+			sum.files = append(sum.files, reportFiles([]filebuilder.Output{file})...)
+		}
+	}
+	if tool != "" {
+		err = next.Save(c.Cache)
+		if err != nil {
+			written.Warnf(token.Position{}, diagnostics.CodeCache, "Unable to write cache %s - %s", c.Cache, err.Error())
+		}
+	}
+	diags.Merge(written)
+	err = stageFailed(StageWrite, written, c.Strict)
+	if err != nil {
+		return sum, err
+	}
+	if c.API != "" {
+		checkAPI(c.API, c.AcceptBreaking, sum.modules, out, diags)
+	}
+	if c.Build && len(bridged) > 0 {
+		bind(c.Bridge.Bind, paths, refs, out, diags)
+	}
+	if c.Register {
+		register(packages, out, diags)
+	}
+	if c.Gradle {
+		gradle(c.Bridge.Bind.WithDefaults().Output, out, diags)
+	}
+	return sum, nil
+}
+
+//conclude returns the final line of a run: what it bridged, or the stage
+//it stopped at, and the diagnostics it reported
+func conclude(sum summary, err error, diags *diagnostics.List) string {
+	counts := fmt.Sprintf("%d errors and %d warnings", diags.Count(diagnostics.Error), diags.Count(diagnostics.Warning))
+	var stage *Error
+	if errors.As(err, &stage) {
+		return fmt.Sprintf("Stopped as %s failed, with %s", stage.Stage, counts)
+	}
+	return fmt.Sprintf("Bridged %d packages, %d regenerated, with %s", len(sum.modules), len(sum.regenerated), counts)
+}
+
+//describe returns the report of each validated package, with the functions
+//bridged and the symbols skipped. Each is skipped until it is generated.
+func describe(valid []types.GoType, issues [][]validator.Issue) []report.Package {
+	packages := make([]report.Package, len(valid))
+	for i := range valid {
+		t := &valid[i]
+		p := report.Package{Name: t.PackageName, ImportPath: t.ImportPath, Status: report.StatusSkipped, Functions: make([]report.Function, 0)}
+		for j := range t.Functions {
+			if t.IsIgnored(j) {
+				continue
 			}
+			f := &t.Functions[j]
+			method := filebuilder.APIMethod(t, j)
+			p.Functions = append(p.Functions, report.Function{
+				Name:      f.Name,
+				JSName:    method.Name,
+				Signature: method.Signature(),
+				Sync:      f.Directives.Sync,
+				Thread:    f.Thread(),
+			})
 		}
+		for _, issue := range issues[i] {
+			d := issue.Diagnostic()
+			p.Skipped = append(p.Skipped, report.Skipped{
+				Symbol: issue.Symbol,
+				Code:   d.Code,
+				Reason: issue.Message,
+				File:   issue.Pos.Filename,
+				Line:   issue.Pos.Line,
+				Column: issue.Pos.Column,
+			})
+		}
+		packages[i] = p
+	}
+	return packages
+}
+
+//reportFiles returns the report of each file written
+func reportFiles(outputs []filebuilder.Output) []report.File {
+	files := make([]report.File, 0, len(outputs))
+	for _, o := range outputs {
+		files = append(files, report.File{Path: o.Path, Status: string(o.Status)})
+	}
+	return files
+}
+
+//newReport returns the report of a run returning sum and err and reporting
+//diags
+func newReport(sum summary, err error, diags *diagnostics.List) report.Report {
+	r := report.Report{
+		Version:  report.Version,
+		Errors:   diags.Count(diagnostics.Error),
+		Warnings: diags.Count(diagnostics.Warning),
+		Packages: sum.packages,
+		Files:    sum.files,
+	}
+	var stage *Error
+	if errors.As(err, &stage) {
+		r.FailedStage = stage.Stage.String()
+	}
+	if r.Packages == nil {
+		r.Packages = make([]report.Package, 0)
 	}
+	return r
 }
 
-func module(t types.GoType) string {
+//watch generates the bridge, then polls the configured packages and
+//generates it again once they change, until stop is closed. Each run
+//writes its diagnostics to diagOut and a summary of the packages
+//regenerated and the API changes to out. Only the packages changed since
+//the last run that succeeded, and those using their types, are written
+//again. It returns an error when the packages cannot be found.
+func watch(c config.Config, stop <-chan struct{}, out io.Writer, diagOut io.Writer) error {
+	patterns := c.Packages
+	if len(patterns) == 0 {
+		patterns = []string{defaultGoPackage}
+	}
+	w := watcher.Watcher{Interval: c.Interval, Scan: func() (watcher.Snapshot, error) {
+		paths, err := goparser.Expand(patterns)
+		if err != nil {
+			return nil, err
+		}
+		return watcher.Take(paths, goparser.Folder)
+	}}
+	last, err := w.Scan()
+	if err != nil {
+		return err
+	}
+	//pending holds the packages changed since the last run that succeeded,
+	//and is nil until one has, so every package is written
+	var pending map[string]bool
+	prev, err := watchRun(c, pending, out, diagOut)
+	if err == nil {
+		reportChanges(summary{modules: prev.modules}, prev, out)
+		pending = make(map[string]bool)
+	}
+	fmt.Fprintf(out, "Watching for changes every %s\n", c.Interval)
+	for {
+		next, changed, ok := w.Wait(last, stop)
+		if !ok {
+			return nil
+		}
+		last = next
+		fmt.Fprintf(out, "Changed %s\n", strings.Join(changed, ", "))
+		if pending == nil {
+			sum, err := watchRun(c, nil, out, diagOut)
+			if err == nil {
+				reportChanges(summary{modules: sum.modules}, sum, out)
+				prev, pending = sum, make(map[string]bool)
+			}
+			continue
+		}
+		for _, path := range changed {
+			pending[path] = true
+		}
+		sum, err := watchRun(c, pending, out, diagOut)
+		if err != nil {
+			continue
+		}
+		reportChanges(prev, sum, out)
+		prev, pending = sum, make(map[string]bool)
+	}
+}
+
+//watchRun generates the bridge once for watch, writing the packages
+//affected by changed as regenerate does, and its diagnostics to diagOut.
+//A failed stage is reported, and the API of the last run that got
+//through writing is kept to report the next changes against.
+func watchRun(c config.Config, changed map[string]bool, out io.Writer, diagOut io.Writer) (summary, error) {
+	diags := &diagnostics.List{}
+	sum, err := regenerate(c, changed, ioutil.Discard, diags)
+	werr := diags.Write(diagOut, c.Format)
+	if werr != nil {
+		fmt.Fprintf(out, "Unable to write diagnostics - %s\n", werr.Error())
+	}
+	if err != nil {
+		fmt.Fprintln(out, conclude(sum, err, diags))
+	}
+	return sum, err
+}
+
+//affected identifies whether t or a package whose types it uses has an
+//import path in changed
+func affected(t *types.GoType, changed map[string]bool) bool {
+	if changed[t.ImportPath] {
+		return true
+	}
+	for _, o := range t.Linked {
+		if changed[o.ImportPath] {
+			return true
+		}
+	}
+	return false
+}
+
+//reportChanges writes what changed in the bridge from prev to next: the
+//packages regenerated, and the changes to the API JS sees
+func reportChanges(prev summary, next summary, out io.Writer) {
+	changes := api.Compare(api.NewSnapshot(prev.modules), api.NewSnapshot(next.modules))
+	if len(next.regenerated) == 0 && len(changes) == 0 {
+		fmt.Fprintf(out, "Bridge unchanged\n")
+		return
+	}
+	if len(next.regenerated) > 0 {
+		fmt.Fprintf(out, "Regenerated %s\n", strings.Join(next.regenerated, ", "))
+	}
+	for _, change := range changes {
+		fmt.Fprintf(out, "\t%s\n", change)
+	}
+}
+
+//load returns the packages of the run and the import paths gomobile binds
+//them from, read from the IR file when one is configured and otherwise
+//parsed from the configured Go packages. Failures are reported to diags.
+func load(c config.Config, out io.Writer, diags *diagnostics.List) ([]types.GoType, []string) {
+	if c.FromIR != "" {
+		fmt.Fprintf(out, "Reading IR %s\n", c.FromIR)
+		tList, err := ir.Load(c.FromIR, goparser.Folder)
+		if err != nil {
+			diags.Errorf(token.Position{}, diagnostics.CodeParse, "%s", err.Error())
+			return nil, nil
+		}
+		paths := make([]string, 0, len(tList))
+		for _, t := range tList {
+			if t.ImportPath != "" {
+				paths = append(paths, t.ImportPath)
+			}
+		}
+		return tList, paths
+	}
+	patterns := c.Packages
+	if len(patterns) == 0 {
+		patterns = []string{defaultGoPackage}
+	}
+	paths, err := goparser.Expand(patterns)
+	if err != nil {
+		diags.Errorf(token.Position{}, diagnostics.CodeParse, "%s", err.Error())
+		return nil, nil
+	}
+	return parse(paths, c.Jobs, out, diags), paths
+}
+
+//dumpIR writes the configured Go packages to w as IR, as parsed and before
+//the configuration is applied. Nothing is written when parsing fails.
+func dumpIR(c config.Config, w io.Writer, out io.Writer, diags *diagnostics.List) error {
+	parsed := &diagnostics.List{}
+	tList, _ := load(c, out, parsed)
+	diags.Merge(parsed)
+	err := stageFailed(StageParse, parsed, c.Strict)
+	if err != nil {
+		return err
+	}
+	err = ir.Write(w, tList)
+	if err != nil {
+		written := &diagnostics.List{}
+		written.Errorf(token.Position{}, diagnostics.CodeWrite, "Unable to write IR - %s", err.Error())
+		diags.Merge(written)
+		return stageFailed(StageWrite, written, c.Strict)
+	}
+	return nil
+}
+
+//parse parses the Go packages at paths, jobs at a time. Packages sharing a
+//name with one earlier in paths are reported and dropped, as gomobile
+//cannot bind both.
+func parse(paths []string, jobs int, out io.Writer, diags *diagnostics.List) []types.GoType {
+	parsed := make([][]types.GoType, len(paths))
+	progress := make([]string, len(paths))
+	lists := make([]*diagnostics.List, len(paths))
+	forEach(jobs, len(paths), func(i int) {
+		lists[i] = &diagnostics.List{}
+		progress[i] = fmt.Sprintf("Processing package %s\n", paths[i])
+		pkgs, err := goparser.Parsing(paths[i], lists[i])
+		if err != nil {
+			progress[i] += fmt.Sprintf("Unable to parse file - %s\n", err.Error())
+		}
+		parsed[i] = pkgs
+	})
+	tList := make([]types.GoType, 0, len(paths))
+	seen := make(map[string]string)
+	for i, path := range paths {
+		fmt.Fprint(out, progress[i])
+		diags.Merge(lists[i])
+		for _, t := range parsed[i] {
+			if other, ok := seen[t.PackageName]; ok {
+				diags.Errorf(token.Position{}, diagnostics.CodeParse,
+					"package %s at %s has the same name as %s, which gomobile cannot bind together", t.PackageName, path, other)
+				continue
+			}
+			seen[t.PackageName] = path
+			tList = append(tList, t)
+		}
+	}
+	return tList
+}
+
+//validate validates the packages of tList, jobs at a time, returning them
+//in the same order
+func validate(tList []types.GoType, jobs int, diags *diagnostics.List) ([]types.GoType, [][]validator.Issue) {
+	valid := make([]types.GoType, len(tList))
+	issues := make([][]validator.Issue, len(tList))
+	forEach(jobs, len(tList), func(i int) {
+		valid[i], issues[i] = validator.Validate(tList[i])
+	})
+	for _, list := range issues {
+		for _, issue := range list {
+			diags.Add(issue.Diagnostic())
+		}
+	}
+	return valid, issues
+}
+
+//forEach calls work with every index below n, on at most jobs goroutines
+//at once, and returns once every call has. work must only write state
+//belonging to its index.
+func forEach(jobs int, n int, work func(i int)) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				work(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+//checkAPI reports the changes between the snapshot at path and modules,
+//then records modules there unless a change breaks JS callers. Accepted
+//breaking changes are reported as notes, and recorded.
+func checkAPI(path string, accept bool, modules []api.Module, out io.Writer, diags *diagnostics.List) {
+	next := api.NewSnapshot(modules)
+	old, err := api.Load(path)
+	switch {
+	case os.IsNotExist(err):
+		fmt.Fprintf(out, "Recording API snapshot %s\n", path)
+	case err != nil:
+		diags.Errorf(token.Position{}, diagnostics.CodeSnapshot, "Unable to read API snapshot - %s", err.Error())
+		return
+	default:
+		blocked := false
+		for _, change := range api.Compare(old, next) {
+			switch {
+			case !change.Breaking:
+				diags.Notef(token.Position{}, diagnostics.CodeCompatibleChange, "%s: %s", change.Symbol, change.Message)
+			case accept:
+				diags.Notef(token.Position{}, diagnostics.CodeBreakingChange, "%s: %s, accepted", change.Symbol, change.Message)
+			default:
+				blocked = true
+				diags.Errorf(token.Position{}, diagnostics.CodeBreakingChange,
+					"%s: %s, which breaks JS callers, accept it with -accept-breaking", change.Symbol, change.Message)
+			}
+		}
+		if blocked {
+			return
+		}
+	}
+	err = next.Write(path)
+	if err != nil {
+		diags.Errorf(token.Position{}, diagnostics.CodeSnapshot, "Unable to write API snapshot - %s", err.Error())
+	}
+}
+
+//gradle wires the .aar at aar and the generated sources into the app's
+//build, warning instead when the build file cannot be found or edited
+func gradle(aar string, out io.Writer, diags *diagnostics.List) {
+	changed, err := registration.WireGradle(defaultAppModule, aar, []string{defaultAndroidRoot})
+	if err != nil {
+		diags.Warnf(token.Position{}, diagnostics.CodeRegister,
+			"Unable to apply %s, add the aar dependency by hand - %s", registration.GradleScript, err.Error())
+		return
+	}
+	if changed {
+		fmt.Fprintf(out, "\tApplied %s in %s\n", registration.GradleScript, defaultAppModule)
+	}
+}
+
+//bind runs gomobile bind for the Go packages at paths, then checks every
+//symbol the bridge references is in the bound API
+func bind(o binder.Options, paths []string, refs []filebuilder.Reference, out io.Writer, diags *diagnostics.List) {
+	o = o.WithDefaults()
+	fmt.Fprintf(out, "\tBinding %s to %s\n", strings.Join(paths, " "), o.Output)
+	err := binder.Bind(o, paths)
+	if err != nil {
+		diags.Errorf(token.Position{}, diagnostics.CodeBind, "%s", err.Error())
+		return
+	}
+	api, err := binder.ReadAAR(o.Output)
+	if err != nil {
+		diags.Errorf(token.Position{}, diagnostics.CodeBind, "Unable to read bound API - %s", err.Error())
+		return
+	}
+	for _, ref := range refs {
+		if !api.Has(ref.Class, ref.Member) {
+			diags.Errorf(ref.Pos, diagnostics.CodeMissingSymbol, "%s is not in the API bound by gomobile", ref)
+		}
+	}
+}
+
+//register adds packages to the app's MainApplication, warning instead when
+//it cannot be found or edited
+func register(packages []registration.Package, out io.Writer, diags *diagnostics.List) {
+	path, err := registration.Find(defaultAppRoot)
+	if err != nil {
+		diags.Warnf(token.Position{}, diagnostics.CodeRegister, "Unable to register packages - %s", err.Error())
+		return
+	}
+	changed, err := registration.Register(path, packages)
+	if err != nil {
+		diags.Warnf(token.Position{Filename: path}, diagnostics.CodeRegister,
+			"Unable to register packages, add them to getPackages by hand - %s", err.Error())
+		return
+	}
+	if changed {
+		fmt.Fprintf(out, "\tRegistered packages in %s\n", path)
+	}
+}
+
+//unregister removes the packages added by register from the app's
+//MainApplication, and the Gradle wiring added by gradle
+func unregister(out io.Writer, diags *diagnostics.List) {
+	changed, err := registration.UnwireGradle(defaultAppModule)
+	if err != nil {
+		diags.Warnf(token.Position{}, diagnostics.CodeRegister, "Unable to remove %s - %s", registration.GradleScript, err.Error())
+	} else if changed {
+		fmt.Fprintf(out, "Removed %s from %s\n", registration.GradleScript, defaultAppModule)
+	}
+	path, err := registration.Find(defaultAppRoot)
+	if err != nil {
+		diags.Warnf(token.Position{}, diagnostics.CodeRegister, "Unable to unregister packages - %s", err.Error())
+		return
+	}
+	changed, err = registration.Unregister(path)
+	if err != nil {
+		diags.Warnf(token.Position{Filename: path}, diagnostics.CodeRegister, "Unable to unregister packages - %s", err.Error())
+		return
+	}
+	if changed {
+		fmt.Fprintf(out, "Unregistered packages in %s\n", path)
+	}
+}
+
+//writeFailed reports the error from writing what. Generated Java that does
+//not parse is reported at the error in the generated file.
+func writeFailed(diags *diagnostics.List, pos token.Position, what string, err error) {
+	var syntax *javasyntax.Error
+	if errors.As(err, &syntax) {
+		diags.Errorf(token.Position{Filename: syntax.File, Line: syntax.Line, Column: syntax.Col},
+			diagnostics.CodeInvalidJava, "Generated %s is not valid Java - %s", what, syntax.Msg)
+		return
+	}
+	diags.Errorf(pos, diagnostics.CodeWrite, "Unable to build %s - %s", what, err.Error())
+}
+
+//generated is what generating one package produced, kept apart from other
+//packages generated at the same time and merged into the run in order
+type generated struct {
+	progress string
+	diags    *diagnostics.List
+	class    string
+	outputs  []filebuilder.Output
+	entry    cache.Entry
+	cached   bool
+	//fresh is set when the cache held the package unchanged, and failed
+	//when a file could not be written
+	fresh  bool
+	failed bool
+}
+
+//bridgePackage writes the bridge of t unless it is kept as last written or
+//the cache holds it unchanged. The package class to register is returned
+//in either case, unless aggregate.
+func bridgePackage(t *types.GoType, aggregate bool, previous *cache.Cache, tool string, kept bool) generated {
+	r := generated{diags: &diagnostics.List{}}
+	key, err := packageKey(tool, aggregate, t)
+	if err != nil {
+		//Without a key the package is always written, and not cached
+		r.diags.Warnf(token.Position{}, diagnostics.CodeCache, "Unable to cache %s - %s", t.PackageName, err.Error())
+		tool = ""
+	}
+	if kept || tool != "" && previous.Fresh(t.PackageName, key) {
+		r.progress = fmt.Sprintf("\tPackagename unchanged: %s\n", t.PackageName)
+		if !aggregate {
+			p := filebuilder.NewPackageBuilder(defaultAndroidRoot, defaultPackageRoot)
+			r.class = p.QualifiedClassName(t.PackageName)
+		}
+		r.entry, r.cached = previous.Packages[t.PackageName]
+		r.fresh = true
+		for path := range r.entry.Files {
+			r.outputs = append(r.outputs, filebuilder.Output{Path: path, Status: filebuilder.FileUnchanged})
+		}
+		sort.Slice(r.outputs, func(i, j int) bool {
+			return r.outputs[i].Path < r.outputs[j].Path
+		})
+		return r
+	}
+	r.progress = fmt.Sprintf("\tPackagename created: %s\n", t.PackageName)
+	r.class, r.outputs, r.failed = writePackage(t, aggregate, r.diags)
+	if r.failed || tool == "" {
+		return r
+	}
+	files := make([]string, 0, len(r.outputs))
+	for _, o := range r.outputs {
+		files = append(files, o.Path)
+	}
+	entry, err := cache.NewEntry(key, files)
+	if err != nil {
+		r.diags.Warnf(token.Position{}, diagnostics.CodeCache, "Unable to cache %s - %s", t.PackageName, err.Error())
+		return r
+	}
+	r.entry, r.cached = entry, true
+	return r
+}
+
+//writePackage writes the module, package, emitters and scripts of t.
+//It returns the package class to register, unless aggregate, the files
+//written, and whether any file failed. Everything else calls into
+//the module, so nothing else is written when it fails.
+func writePackage(t *types.GoType, aggregate bool, diags *diagnostics.List) (string, []filebuilder.Output, bool) {
+	file, err := moduleBuild(t)
+	if err != nil {
+		writeFailed(diags, token.Position{}, "module", err)
+		return "", nil, true
+	}
+	files := []filebuilder.Output{file}
+	ok := true
+	class := ""
+	if !aggregate {
+		class, file, err = packageBuild(t.PackageName)
+		if err != nil {
+			writeFailed(diags, token.Position{}, "package", err)
+			ok = false
+		} else {
+			files = append(files, file)
+		}
+	}
+	for _, c := range t.Callbacks() {
+		file, err = emitterBuild(t, c)
+		if err != nil {
+			writeFailed(diags, c.Pos, "emitter", err)
+			ok = false
+		} else {
+			files = append(files, file)
+		}
+	}
+	scripts, err := scriptBuild(t)
+	if err != nil {
+		writeFailed(diags, token.Position{}, "JS", err)
+		ok = false
+	} else {
+		files = append(files, scripts...)
+	}
+	return class, files, !ok
+}
+
+//loadCache returns the cache at path, an empty cache to record this run in
+//and the hash of the tool. Without a cache, or when the tool cannot be
+//hashed, the hash is empty and every package is generated.
+func loadCache(path string, diags *diagnostics.List) (*cache.Cache, *cache.Cache, string) {
+	if path == "" {
+		return cache.New(), cache.New(), ""
+	}
+	tool, err := cache.Executable()
+	if err != nil {
+		diags.Warnf(token.Position{}, diagnostics.CodeCache, "Unable to identify the tool, regenerating every package - %s", err.Error())
+		return cache.New(), cache.New(), ""
+	}
+	previous, err := cache.Load(path)
+	if err != nil {
+		diags.Warnf(token.Position{}, diagnostics.CodeCache, "Unable to read cache, regenerating every package - %s", err.Error())
+		previous = cache.New()
+	}
+	return previous, cache.New(), tool
+}
+
+//packageKey returns the cache key of t: the tool generating it, the
+//options and roots it is generated with, and t and the packages linked to
+//it as configured and validated, so the key changes with anything the
+//generated files depend on. It fails when the packages cannot be encoded.
+func packageKey(tool string, aggregate bool, t *types.GoType) (string, error) {
+	names := make([]string, 0, len(t.Linked))
+	for name := range t.Linked {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pkgs := []types.GoType{*t}
+	for _, name := range names {
+		pkgs = append(pkgs, *t.Linked[name])
+	}
+	var model bytes.Buffer
+	err := ir.Write(&model, pkgs)
+	if err != nil {
+		return "", err
+	}
+	options := fmt.Sprintf("aggregate=%t android=%s package=%s js=%s",
+		aggregate, defaultAndroidRoot, defaultPackageRoot, defaultJSRoot)
+	return cache.Key([]byte(tool), []byte(options), model.Bytes()), nil
+}
+
+func moduleBuild(t *types.GoType) (filebuilder.Output, error) {
 	m := filebuilder.NewModuleBuilder(defaultAndroidRoot,
 		defaultPackageRoot)
-	typeString, err := m.BuildModule(&t)
+	_, err := m.BuildModule(t)
 	if err != nil {
-		fmt.Printf("Unable to build module - %s\n", err.Error())
-		return ""
+		return filebuilder.Output{}, err
 	}
 	err = m.Close()
 	if err != nil {
-		fmt.Printf("Unable to build module - %s\n", err.Error())
-		return ""
+		return filebuilder.Output{}, err
 	}
-	return typeString
+	return m.Output(), nil
 }
 
-func packageBuild(typeString string, packageName string) error {
+func packageBuild(packageName string) (string, filebuilder.Output, error) {
 	m := filebuilder.NewPackageBuilder(defaultAndroidRoot,
 		defaultPackageRoot)
 
 	err := m.BuildPackage(packageName)
 	if err != nil {
-		return err
+		return "", filebuilder.Output{}, err
 	}
 	err = m.Close()
 	if err != nil {
-		return err
+		return "", filebuilder.Output{}, err
 	}
-	return nil
+	return m.QualifiedClassName(packageName), m.Output(), nil
+}
+
+func aggregateBuild(packageNames []string) (string, filebuilder.Output, error) {
+	m := filebuilder.NewPackageBuilder(defaultAndroidRoot,
+		defaultPackageRoot)
+	err := m.BuildAggregatePackage(packageNames)
+	if err != nil {
+		return "", filebuilder.Output{}, err
+	}
+	err = m.Close()
+	if err != nil {
+		return "", filebuilder.Output{}, err
+	}
+	return m.AggregateClassName(), m.Output(), nil
+}
+
+func emitterBuild(t *types.GoType, c types.GoTypeSpec) (filebuilder.Output, error) {
+	e := filebuilder.NewEmitterBuilder(defaultAndroidRoot,
+		defaultPackageRoot)
+	_, err := e.BuildEmitter(t, c)
+	if err != nil {
+		return filebuilder.Output{}, err
+	}
+	err = e.Close()
+	if err != nil {
+		return filebuilder.Output{}, err
+	}
+	return e.Output(), nil
+}
+
+func executorBuild(size int) (filebuilder.Output, error) {
+	e := filebuilder.NewExecutorBuilder(defaultAndroidRoot,
+		defaultPackageRoot)
+	_, err := e.BuildExecutors(size)
+	if err != nil {
+		return filebuilder.Output{}, err
+	}
+	err = e.Close()
+	if err != nil {
+		return filebuilder.Output{}, err
+	}
+	return e.Output(), nil
+}
+
+func scriptBuild(t *types.GoType) ([]filebuilder.Output, error) {
+	s := filebuilder.NewScriptBuilder(defaultJSRoot)
+	_, err := s.BuildScript(t)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Close()
+	if err != nil {
+		return nil, err
+	}
+	d := filebuilder.NewDeclarationBuilder(defaultJSRoot)
+	_, err = d.BuildDeclarations(t)
+	if err != nil {
+		return nil, err
+	}
+	err = d.Close()
+	if err != nil {
+		return nil, err
+	}
+	return []filebuilder.Output{s.Output(), d.Output()}, nil
 }
 
 func goToJavaType(javaType string) string {
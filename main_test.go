@@ -1,9 +1,608 @@
 package main
 
-import "testing"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
 
-func TestGoToJavaType(t *testing.T) {
-	if goToJavaType("string") != "string" {
-		t.Fail()
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/api"
+	"github.com/steve-winter/reactgonative/config"
+	"github.com/steve-winter/reactgonative/diagnostics"
+	"github.com/steve-winter/reactgonative/filebuilder"
+	"github.com/steve-winter/reactgonative/report"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files from the generated output")
+
+//goldenRoot holds a directory of Go packages per fixture under src, and the
+//files generated from it under out
+const goldenRoot = "testdata/golden"
+
+func TestGolden(t *testing.T) {
+	fixtures, err := ioutil.ReadDir(filepath.Join(goldenRoot, "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fixture := range fixtures {
+		name := fixture.Name()
+		Convey("Given the "+name+" fixture", t, func() {
+			out := filepath.Join(t.TempDir(), name)
+			golden := filepath.Join(goldenRoot, "out", name)
+			Convey("When the bridge is generated", func() {
+				So(generate(name, out, ""), ShouldBeNil)
+				if *update {
+					So(os.RemoveAll(golden), ShouldBeNil)
+					So(copyTree(out, golden), ShouldBeNil)
+				}
+				got, want := readTree(out), readTree(golden)
+				Convey("Then the same files are generated", func() {
+					So(fileNames(got), ShouldResemble, fileNames(want))
+				})
+				Convey("And every file matches its golden file", func() {
+					for _, file := range fileNames(got) {
+						So(file+"\n"+got[file], ShouldEqual, file+"\n"+want[file])
+					}
+				})
+			})
+		})
+	}
+}
+
+func TestGoldenFromIR(t *testing.T) {
+	fixtures, err := ioutil.ReadDir(filepath.Join(goldenRoot, "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fixture := range fixtures {
+		name := fixture.Name()
+		Convey("Given the IR dumped from the "+name+" fixture", t, func() {
+			dir := t.TempDir()
+			path := filepath.Join(dir, name+".json")
+			So(dumpFixture(name, path), ShouldBeNil)
+			Convey("When the bridge is generated from the IR", func() {
+				out := filepath.Join(dir, "out")
+				So(generate(name, out, path), ShouldBeNil)
+				got, want := readTree(out), readTree(filepath.Join(goldenRoot, "out", name))
+				//Parse warnings are only reported by the run parsing the Go source
+				delete(got, "diagnostics.txt")
+				delete(want, "diagnostics.txt")
+				Convey("Then the golden files are generated", func() {
+					So(fileNames(got), ShouldResemble, fileNames(want))
+					for _, file := range fileNames(got) {
+						So(file+"\n"+got[file], ShouldEqual, file+"\n"+want[file])
+					}
+				})
+			})
+		})
+	}
+}
+
+func TestIncremental(t *testing.T) {
+	Convey("Given the crosspkg fixture generated with a cache", t, func() {
+		out := t.TempDir()
+		androidRoot, jsRoot := defaultAndroidRoot, defaultJSRoot
+		defer func() {
+			defaultAndroidRoot, defaultJSRoot = androidRoot, jsRoot
+		}()
+		defaultAndroidRoot = filepath.Join(out, "java") + "/"
+		defaultJSRoot = filepath.Join(out, "js") + "/"
+		c := config.Config{Packages: []string{fixturePattern("crosspkg")}, Cache: filepath.Join(out, "cache.json")}
+		regenerate := func() string {
+			var progress strings.Builder
+			diags := &diagnostics.List{}
+			run(c, &progress, diags)
+			So(diags.Count(diagnostics.Error), ShouldEqual, 0)
+			return progress.String()
+		}
+		So(regenerate(), ShouldNotContainSubstring, "unchanged")
+		dayAgo := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+		files := fileNames(readTree(out))
+		for _, file := range files {
+			So(os.Chtimes(filepath.Join(out, file), dayAgo, dayAgo), ShouldBeNil)
+		}
+		Convey("When it is generated again", func() {
+			progress := regenerate()
+			Convey("Then every package is skipped", func() {
+				So(progress, ShouldNotContainSubstring, "created")
+				So(progress, ShouldContainSubstring, "Packagename unchanged")
+			})
+			Convey("And no file is touched", func() {
+				for _, file := range files {
+					info, err := os.Stat(filepath.Join(out, file))
+					So(err, ShouldBeNil)
+					So(file+" "+info.ModTime().String(), ShouldEqual, file+" "+dayAgo.String())
+				}
+			})
+		})
+		Convey("When a generated file is edited and it is generated again", func() {
+			module := filepath.Join(out, "js", "jobs.js")
+			want, err := ioutil.ReadFile(module)
+			So(err, ShouldBeNil)
+			So(ioutil.WriteFile(module, []byte("edited\n"), 0644), ShouldBeNil)
+			progress := regenerate()
+			Convey("Then its package is regenerated", func() {
+				So(progress, ShouldContainSubstring, "Packagename created: jobs")
+				got, err := ioutil.ReadFile(module)
+				So(err, ShouldBeNil)
+				So(string(got), ShouldEqual, string(want))
+			})
+		})
+		Convey("When it is generated with other options", func() {
+			c.Aggregate = true
+			progress := regenerate()
+			Convey("Then every package is regenerated", func() {
+				So(progress, ShouldNotContainSubstring, "unchanged")
+			})
+		})
+	})
+}
+
+func TestRegenerate(t *testing.T) {
+	Convey("Given the crosspkg fixture generated without a cache", t, func() {
+		out := t.TempDir()
+		androidRoot, jsRoot := defaultAndroidRoot, defaultJSRoot
+		defer func() {
+			defaultAndroidRoot, defaultJSRoot = androidRoot, jsRoot
+		}()
+		defaultAndroidRoot = filepath.Join(out, "java") + "/"
+		defaultJSRoot = filepath.Join(out, "js") + "/"
+		c := config.Config{Packages: []string{fixturePattern("crosspkg")}}
+		first, err := run(c, ioutil.Discard, &diagnostics.List{})
+		So(err, ShouldBeNil)
+		paths := make(map[string]string)
+		for _, p := range first.packages {
+			paths[p.Name] = p.ImportPath
+		}
+		regenerated := func(changed ...string) summary {
+			only := make(map[string]bool)
+			for _, name := range changed {
+				only[paths[name]] = true
+			}
+			sum, err := regenerate(c, only, ioutil.Discard, &diagnostics.List{})
+			So(err, ShouldBeNil)
+			return sum
+		}
+		Convey("When nothing changed", func() {
+			sum := regenerated()
+			Convey("Then no package is written", func() {
+				So(sum.regenerated, ShouldBeEmpty)
+			})
+			Convey("And every module is still bridged", func() {
+				So(sum.modules, ShouldResemble, first.modules)
+			})
+		})
+		Convey("When a package used by another changed", func() {
+			sum := regenerated("status")
+			Convey("Then both are written", func() {
+				So(sum.regenerated, ShouldResemble, []string{"jobs", "status"})
+			})
+		})
+		Convey("When a package using another changed", func() {
+			sum := regenerated("jobs")
+			Convey("Then only it is written", func() {
+				So(sum.regenerated, ShouldResemble, []string{"jobs"})
+			})
+		})
+	})
+}
+
+func TestWatch(t *testing.T) {
+	Convey("Given packages that cannot be found", t, func() {
+		c := config.Config{Packages: []string{"./" + filepath.Join(t.TempDir(), "missing")}, Interval: time.Millisecond}
+		Convey("When they are watched", func() {
+			var out strings.Builder
+			err := watch(c, make(chan struct{}), &out, ioutil.Discard)
+			Convey("Then watching fails before generating", func() {
+				So(err, ShouldNotBeNil)
+				So(out.String(), ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestStages(t *testing.T) {
+	Convey("Given a run writing under a temporary directory", t, func() {
+		out := t.TempDir()
+		androidRoot, jsRoot := defaultAndroidRoot, defaultJSRoot
+		defer func() {
+			defaultAndroidRoot, defaultJSRoot = androidRoot, jsRoot
+		}()
+		defaultAndroidRoot = filepath.Join(out, "java") + "/"
+		defaultJSRoot = filepath.Join(out, "js") + "/"
+		diags := &diagnostics.List{}
+		Convey("When the IR cannot be read", func() {
+			c := config.Config{FromIR: filepath.Join(out, "missing.json"), API: filepath.Join(out, "api.json")}
+			sum, err := run(c, ioutil.Discard, diags)
+			Convey("Then parsing fails and nothing else runs", func() {
+				So(err, ShouldResemble, &Error{Stage: StageParse, Errors: 1})
+				So(exitCode(err, diags, false), ShouldEqual, exitParse)
+				So(readTree(out), ShouldBeEmpty)
+				So(conclude(sum, err, diags), ShouldEqual, "Stopped as parsing failed, with 1 errors and 0 warnings")
+			})
+		})
+		Convey("When a package has warnings under strict", func() {
+			c := config.Config{Packages: []string{fixturePattern("basic")}, Strict: true}
+			_, err := run(c, ioutil.Discard, diags)
+			Convey("Then validation fails before anything is written", func() {
+				var stage *Error
+				So(errors.As(err, &stage), ShouldBeTrue)
+				So(stage.Stage, ShouldEqual, StageValidate)
+				So(exitCode(err, diags, true), ShouldEqual, exitValidate)
+				So(readTree(out), ShouldBeEmpty)
+			})
+		})
+		Convey("When the modules cannot be written", func() {
+			So(ioutil.WriteFile(filepath.Join(out, "java"), []byte("not a directory"), 0644), ShouldBeNil)
+			c := config.Config{Packages: []string{fixturePattern("crosspkg")}, API: filepath.Join(out, "api.json")}
+			_, err := run(c, ioutil.Discard, diags)
+			Convey("Then writing fails", func() {
+				var stage *Error
+				So(errors.As(err, &stage), ShouldBeTrue)
+				So(stage.Stage, ShouldEqual, StageWrite)
+				So(exitCode(err, diags, false), ShouldEqual, exitWrite)
+			})
+			Convey("And nothing calling into the modules is written", func() {
+				So(fileNames(readTree(out)), ShouldResemble, []string{"java"})
+			})
+		})
+		Convey("When every stage succeeds", func() {
+			c := config.Config{Packages: []string{fixturePattern("crosspkg")}}
+			sum, err := run(c, ioutil.Discard, diags)
+			Convey("Then the run succeeds", func() {
+				So(err, ShouldBeNil)
+				So(exitCode(err, diags, false), ShouldEqual, exitOK)
+				So(conclude(sum, err, diags), ShouldEqual, "Bridged 2 packages, 2 regenerated, with 0 errors and 2 warnings")
+			})
+		})
+	})
+}
+
+func TestReportChanges(t *testing.T) {
+	Convey("Given the summaries of two watch runs", t, func() {
+		prev := summary{modules: []api.Module{api.Module{Name: "jobs", Methods: []api.Method{
+			api.Method{Name: "start", Returns: "Promise<void>"},
+		}}}}
+		Convey("When nothing was regenerated", func() {
+			var out strings.Builder
+			reportChanges(prev, summary{modules: prev.modules}, &out)
+			Convey("Then the bridge is unchanged", func() {
+				So(out.String(), ShouldEqual, "Bridge unchanged\n")
+			})
+		})
+		Convey("When a package was regenerated with another API", func() {
+			next := summary{regenerated: []string{"jobs"}, modules: []api.Module{api.Module{Name: "jobs", Methods: []api.Method{
+				api.Method{Name: "start", Returns: "Promise<void>"},
+				api.Method{Name: "stop", Returns: "Promise<void>"},
+			}}}}
+			var out strings.Builder
+			reportChanges(prev, next, &out)
+			Convey("Then the package and the API changes are listed", func() {
+				So(out.String(), ShouldEqual, "Regenerated jobs\n\tcompatible jobs.stop: method added\n")
+			})
+		})
+	})
+}
+
+func TestReportJSON(t *testing.T) {
+	Convey("Given the basic fixture generated with a cache", t, func() {
+		out := t.TempDir()
+		androidRoot, jsRoot := defaultAndroidRoot, defaultJSRoot
+		defer func() {
+			defaultAndroidRoot, defaultJSRoot = androidRoot, jsRoot
+		}()
+		defaultAndroidRoot = filepath.Join(out, "java") + "/"
+		defaultJSRoot = filepath.Join(out, "js") + "/"
+		c := config.Config{Packages: []string{fixturePattern("basic")}, Cache: filepath.Join(out, "cache.json")}
+		generateReport := func() report.Report {
+			diags := &diagnostics.List{}
+			sum, err := run(c, ioutil.Discard, diags)
+			return newReport(sum, err, diags)
+		}
+		r := generateReport()
+		So(r.Packages, ShouldHaveLength, 1)
+		p := r.Packages[0]
+		Convey("Then the package is generated", func() {
+			So(r.FailedStage, ShouldBeEmpty)
+			So(r.Warnings, ShouldEqual, 5)
+			So(p.Name, ShouldEqual, "basic")
+			So(p.Status, ShouldEqual, report.StatusGenerated)
+		})
+		Convey("And its functions are listed with their JS signatures", func() {
+			So(p.Functions, ShouldContain, report.Function{Name: "Lookup", JSName: "lookup", Signature: "lookup(name: string): Promise<number>", Thread: types.ThreadBackground})
+			So(p.Functions, ShouldContain, report.Function{Name: "Enabled", JSName: "enabled", Signature: "enabled(): boolean", Sync: true, Thread: types.ThreadInline})
+		})
+		Convey("And the symbols left out are listed with their reasons", func() {
+			symbols := make([]string, 0)
+			for _, skipped := range p.Skipped {
+				symbols = append(symbols, skipped.Symbol+" "+skipped.Code)
+			}
+			So(symbols, ShouldContain, "Hidden "+diagnostics.CodeIgnored)
+			So(symbols, ShouldContain, "Pair "+diagnostics.CodeNotBindable)
+			So(symbols, ShouldContain, "SetLevel "+diagnostics.CodeNotBindable)
+		})
+		Convey("And every file written is created", func() {
+			So(p.Files, ShouldHaveLength, 4)
+			So(r.Files, ShouldBeEmpty)
+			for _, f := range p.Files {
+				So(f.Status, ShouldEqual, string(filebuilder.FileCreated))
+				_, err := os.Stat(f.Path)
+				So(err, ShouldBeNil)
+			}
+		})
+		Convey("When it is generated again", func() {
+			next := generateReport()
+			Convey("Then the package and its files are unchanged", func() {
+				So(next.Packages[0].Status, ShouldEqual, report.StatusUnchanged)
+				So(next.Packages[0].Functions, ShouldResemble, p.Functions)
+				So(next.Packages[0].Files, ShouldHaveLength, 4)
+				for _, f := range next.Packages[0].Files {
+					So(f.Status, ShouldEqual, string(filebuilder.FileUnchanged))
+				}
+			})
+		})
+	})
+}
+
+//fixturePattern matches every package of the fixture
+func fixturePattern(fixture string) string {
+	return "./" + filepath.Join(goldenRoot, "src", fixture) + "/..."
+}
+
+//dumpFixture writes the packages of the fixture to path as IR
+func dumpFixture(fixture string, path string) error {
+	var buf strings.Builder
+	diags := &diagnostics.List{}
+	dumpIR(config.Config{Command: config.CommandDumpIR, Packages: []string{fixturePattern(fixture)}}, &buf, ioutil.Discard, diags)
+	if diags.Count(diagnostics.Error) > 0 {
+		return fmt.Errorf("%s", diags.Diagnostics()[0])
+	}
+	return ioutil.WriteFile(path, []byte(buf.String()), 0644)
+}
+
+//generate runs the whole pipeline for every package of the fixture, or
+//for the packages of the IR file fromIR when set, writing the Java and JS
+//under out along with the API snapshot and the diagnostics report
+func generate(fixture string, out string, fromIR string) error {
+	err := os.RemoveAll(out)
+	if err != nil {
+		return err
+	}
+	src, err := filepath.Abs(filepath.Join(goldenRoot, "src", fixture))
+	if err != nil {
+		return err
+	}
+	androidRoot, jsRoot := defaultAndroidRoot, defaultJSRoot
+	defer func() {
+		defaultAndroidRoot, defaultJSRoot = androidRoot, jsRoot
+	}()
+	defaultAndroidRoot = filepath.Join(out, "java") + "/"
+	defaultJSRoot = filepath.Join(out, "js") + "/"
+	diags := &diagnostics.List{}
+	c := config.Config{Packages: []string{fixturePattern(fixture)}}
+	if fromIR != "" {
+		c = config.Config{FromIR: fromIR}
+	}
+	c.API = filepath.Join(out, "api.json")
+	//Packages are processed concurrently, and the output must not depend on
+	//the order they finish in
+	c.Jobs = 4
+	run(c, ioutil.Discard, diags)
+	var report strings.Builder
+	err = diags.Write(&report, diagnostics.FormatText)
+	if err != nil {
+		return err
+	}
+	//Positions are reported relative to the fixture, wherever it is checked out
+	text := strings.Replace(report.String(), src+string(filepath.Separator), "", -1)
+	return ioutil.WriteFile(filepath.Join(out, "diagnostics.txt"), []byte(text), 0644)
+}
+
+//readTree returns the content of every file under root, keyed by its path
+//relative to root
+func readTree(root string) map[string]string {
+	files := make(map[string]string)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	return files
+}
+
+func fileNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func copyTree(from string, to string) error {
+	for name, content := range readTree(from) {
+		path := filepath.Join(to, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func FuzzGenerate(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte("reactgonative"))
+	f.Add([]byte{3, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20})
+	f.Add([]byte{7, 5, 9, 1, 30, 2, 21, 4, 0, 255, 17, 3, 3, 3, 8, 12, 1, 1, 6, 40, 2, 2, 2, 19})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		src := synthesize(data)
+		gopath := t.TempDir()
+		path := filepath.Join(gopath, "src", "fuzzpkg", "fuzzpkg.go")
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, []byte(src), 0644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := t.TempDir()
+		realGoPath, androidRoot, jsRoot := os.Getenv("GOPATH"), defaultAndroidRoot, defaultJSRoot
+		defer func() {
+			os.Setenv("GOPATH", realGoPath)
+			defaultAndroidRoot, defaultJSRoot = androidRoot, jsRoot
+		}()
+		os.Setenv("GOPATH", gopath)
+		defaultAndroidRoot = filepath.Join(out, "java") + "/"
+		defaultJSRoot = filepath.Join(out, "js") + "/"
+		diags := &diagnostics.List{}
+		run(config.Config{Packages: []string{"fuzzpkg"}}, ioutil.Discard, diags)
+		for _, d := range diags.Diagnostics() {
+			switch d.Code {
+			case diagnostics.CodeParse, diagnostics.CodeWrite, diagnostics.CodeInvalidJava:
+				t.Fatalf("%s\n%s", d, src)
+			}
+		}
+	})
+}
+
+//fuzzSource reads the choices made by synthesize from the fuzzer's bytes,
+//choosing the first option once they run out
+type fuzzSource struct {
+	data []byte
+}
+
+func (s *fuzzSource) pick(n int) int {
+	if len(s.data) == 0 {
+		return 0
+	}
+	b := s.data[0]
+	s.data = s.data[1:]
+	return int(b) % n
+}
+
+func (s *fuzzSource) choose(options []string) string {
+	return options[s.pick(len(options))]
+}
+
+//fuzzTypes are the Go types synthesized signatures use, covering what the
+//bridge supports, what it doesn't and what gomobile can't bind
+var fuzzTypes = []string{
+	"string", "int", "int64", "bool", "float64", "int8", "int16", "int32", "float32", "rune",
+	"uint", "byte", "uint64", "complex128", "[]byte", "[]string", "map[string]int", "error",
+	"Level", "Mode", "Handler", "*Handler", "*Config", "Config", "*config", "func()",
+	"chan int", "interface{}", "struct{}", "fmt.Stringer", "*Level", "...int",
+}
+
+//fuzzNames are parameter names, including names that are keywords or
+//generated names in Java and JS
+var fuzzNames = []string{
+	"name", "", "_", "n", "value", "promise", "e", "message", "executor", "constants",
+	"class", "new", "int", "final", "this", "in", "function", "returnParam1",
+	"reactContext", "event", "args", "synchronized", "Name",
+}
+
+//fuzzFuncNames are function names, including names whose JS names are
+//keywords or clash with the generated module
+var fuzzFuncNames = []string{
+	"Greet", "New", "URL", "HTTPServer", "Do", "Int", "Class", "X", "GetName", "Default",
+	"Package", "Final", "This", "Promise", "Constructor", "GetConstants", "Delete",
+}
+
+//fuzzDirectives are the doc comments declarations are given, most often none
+var fuzzDirectives = []string{
+	"", "", "", "//reactgonative:sync", "//reactgonative:ignore", "//reactgonative:thread background",
+	"//reactgonative:thread pool", "//reactgonative:thread inline", "//reactgonative:name other",
+	"//reactgonative:name getName", "//reactgonative:name two words", "//reactgonative:name not-valid",
+	"//reactgonative:name", "//reactgonative:thread ui", "//reactgonative:unknown",
+}
+
+//synthesize writes a valid Go package with exported declarations chosen by data
+func synthesize(data []byte) string {
+	s := &fuzzSource{data: data}
+	var b strings.Builder
+	b.WriteString("package fuzzpkg\n\nimport (\n\t\"errors\"\n\t\"fmt\"\n)\n\n")
+	b.WriteString("var _ = fmt.Sprint\n\n")
+	b.WriteString("type Config struct{}\n\ntype config struct{}\n\n")
+	b.WriteString("type Mode string\n\n")
+	fmt.Fprintf(&b, "%s\ntype Level int\n\nconst (\n", s.choose(fuzzDirectives))
+	for i, n := 0, s.pick(4); i <= n; i++ {
+		fmt.Fprintf(&b, "\t%s\n\tLevel%s%d Level = %d\n", s.choose(fuzzDirectives), s.choose(fuzzFuncNames), i, s.pick(3)-1)
+	}
+	b.WriteString(")\n\ntype Handler interface {\n")
+	for i, n := 0, s.pick(3); i < n; i++ {
+		fmt.Fprintf(&b, "\tOn%s%d(%s)\n", s.choose(fuzzFuncNames), i, fuzzParams(s))
+	}
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "%s\nvar Err%s = errors.New(%q)\n\n", s.choose(fuzzDirectives),
+		s.choose(fuzzFuncNames), s.choose([]string{"not found", "a \"quoted\"\n\\ message", "é", ""}))
+	fmt.Fprintf(&b, "const %sConst = %s\n\n", s.choose(fuzzFuncNames),
+		s.choose([]string{"1", "\"s\\t\\\"\"", "1.5", "true", "1 << 62", "-3", "'x'", "\"é\""}))
+	funcs := make(map[string]bool)
+	for i, n := 0, s.pick(8)+1; i < n; i++ {
+		receiver := ""
+		if s.pick(6) == 0 {
+			receiver = "(c *Config) "
+		}
+		results := s.choose([]string{"", "error", "T", "(T, error)", "(T, T)", "(T, T, error)"})
+		for strings.Contains(results, "T") {
+			results = strings.Replace(results, "T", strings.TrimPrefix(s.choose(fuzzTypes), "..."), 1)
+		}
+		name := s.choose(fuzzFuncNames)
+		if funcs[name] {
+			name = fmt.Sprintf("%s%d", name, i)
+		}
+		funcs[name] = true
+		fmt.Fprintf(&b, "%s\nfunc %s%s(%s) %s {\n\tpanic(0)\n}\n\n",
+			s.choose(fuzzDirectives), receiver, name, fuzzParams(s), results)
+	}
+	return b.String()
+}
+
+//fuzzParams writes a parameter list, naming all of its parameters or none
+//as Go requires
+func fuzzParams(s *fuzzSource) string {
+	n := s.pick(5)
+	named := s.pick(3) != 0
+	params := make([]string, 0, n)
+	seen := make(map[string]bool)
+	for i := 0; i < n; i++ {
+		t := s.choose(fuzzTypes)
+		if strings.HasPrefix(t, "...") && i != n-1 {
+			t = t[3:]
+		}
+		if !named {
+			params = append(params, t)
+			continue
+		}
+		name := s.choose(fuzzNames)
+		if name == "" || seen[name] {
+			name = fmt.Sprintf("%s%d", name, i)
+			if name[0] >= '0' && name[0] <= '9' {
+				name = "p" + name
+			}
+		}
+		seen[name] = true
+		params = append(params, name+" "+t)
 	}
+	return strings.Join(params, ", ")
 }
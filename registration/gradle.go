@@ -0,0 +1,125 @@
+package registration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//GradleScript is the script declaring the bridge's dependencies, generated
+//in the app module and applied from its build file
+const GradleScript = "bridge.gradle"
+
+//defaultSourceDir is the Java source folder Gradle already compiles
+const defaultSourceDir = "src/main/java"
+
+//WireGradle writes bridge.gradle in the app module at appDir, declaring
+//the .aar at aar and any source folders other than the default, and
+//applies it from the app's build file.
+//Returns whether any file changed.
+func WireGradle(appDir string, aar string, sourceDirs []string) (bool, error) {
+	buildFile, err := findBuildFile(appDir)
+	if err != nil {
+		return false, err
+	}
+	script, err := gradleScript(appDir, aar, sourceDirs)
+	if err != nil {
+		return false, err
+	}
+	changed, err := writeIfChanged(filepath.Join(appDir, GradleScript), script)
+	if err != nil {
+		return changed, err
+	}
+	data, err := ioutil.ReadFile(buildFile)
+	if err != nil {
+		return changed, err
+	}
+	content := string(data)
+	if strings.Contains(content, GradleScript) {
+		return changed, nil
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += applyLine(buildFile) + " " + Marker + "\n"
+	return true, ioutil.WriteFile(buildFile, []byte(content), 0644)
+}
+
+//UnwireGradle removes the line applying bridge.gradle from the app's build
+//file, and bridge.gradle itself. Returns whether any file changed.
+func UnwireGradle(appDir string) (bool, error) {
+	changed := false
+	buildFile, err := findBuildFile(appDir)
+	if err == nil {
+		changed, err = edit(buildFile, nil)
+		if err != nil {
+			return changed, err
+		}
+	}
+	err = os.Remove(filepath.Join(appDir, GradleScript))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return changed, nil
+	}
+	return changed, err
+}
+
+//findBuildFile returns the Groovy or Kotlin build file of the app module
+func findBuildFile(appDir string) (string, error) {
+	for _, name := range []string{"build.gradle", "build.gradle.kts"} {
+		path := filepath.Join(appDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no build.gradle found in %s", appDir)
+}
+
+func applyLine(buildFile string) string {
+	if strings.HasSuffix(buildFile, ".kts") {
+		return "apply(from = \"" + GradleScript + "\")"
+	}
+	return "apply from: \"" + GradleScript + "\""
+}
+
+//gradleScript is the content of bridge.gradle, with paths relative to appDir
+func gradleScript(appDir string, aar string, sourceDirs []string) (string, error) {
+	var b strings.Builder
+	b.WriteString("// Generated by reactgonative, applied from the app's build file. Do not edit.\n\n")
+	rel, err := filepath.Rel(appDir, aar)
+	if err != nil {
+		return "", err
+	}
+	b.WriteString("dependencies {\n")
+	fmt.Fprintf(&b, "    implementation files(%q)\n", filepath.ToSlash(rel))
+	b.WriteString("}\n")
+	dirs := make([]string, 0, len(sourceDirs))
+	for _, dir := range sourceDirs {
+		rel, err = filepath.Rel(appDir, dir)
+		if err != nil {
+			return "", err
+		}
+		if rel = filepath.ToSlash(rel); rel != defaultSourceDir {
+			dirs = append(dirs, fmt.Sprintf("%q", rel))
+		}
+	}
+	if len(dirs) > 0 {
+		b.WriteString("\nandroid {\n    sourceSets {\n        main {\n")
+		fmt.Fprintf(&b, "            java.srcDirs += [%s]\n", strings.Join(dirs, ", "))
+		b.WriteString("        }\n    }\n}\n")
+	}
+	return b.String(), nil
+}
+
+//writeIfChanged writes content to path unless it already holds it
+func writeIfChanged(path string, content string) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err == nil && string(data) == content {
+		return false, nil
+	}
+	return true, ioutil.WriteFile(path, []byte(content), 0644)
+}
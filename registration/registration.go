@@ -0,0 +1,225 @@
+package registration
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//Marker ends every line the tool adds to MainApplication, so later runs can
+//find and replace them without touching lines written by hand
+const Marker = "// reactgonative"
+
+//ErrUnrecognised is returned when MainApplication does not have a
+//getPackages the tool knows how to edit
+var ErrUnrecognised = errors.New("getPackages structure not recognised")
+
+//Package is a generated ReactPackage to register, named by its fully
+//qualified Java class
+type Package struct {
+	Class string
+}
+
+//simpleName is the class name of p without its Java package
+func (p Package) simpleName() string {
+	return p.Class[strings.LastIndex(p.Class, ".")+1:]
+}
+
+//Find returns the path of MainApplication.java or MainApplication.kt under root
+func Find(root string) (string, error) {
+	found := ""
+	errFound := errors.New("found")
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && (info.Name() == "MainApplication.java" || info.Name() == "MainApplication.kt") {
+			found = path
+			return errFound
+		}
+		return nil
+	})
+	if err != nil && err != errFound {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("no MainApplication found under %s", root)
+	}
+	return found, nil
+}
+
+//Register edits the MainApplication at path so it registers exactly
+//packages. Lines added by earlier runs for other packages are removed,
+//and packages already registered by hand are left alone.
+//Returns whether the file changed, or ErrUnrecognised without editing.
+func Register(path string, packages []Package) (bool, error) {
+	return edit(path, packages)
+}
+
+//Unregister removes every line added by Register from the MainApplication
+//at path. Returns whether the file changed.
+func Unregister(path string) (bool, error) {
+	return edit(path, nil)
+}
+
+func edit(path string, packages []Package) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	kotlin := strings.HasSuffix(path, ".kt")
+	lines := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasSuffix(strings.TrimSpace(line), Marker) {
+			lines = append(lines, line)
+		}
+	}
+	if len(packages) > 0 {
+		lines, err = insert(lines, packages, kotlin)
+		if err != nil {
+			return false, err
+		}
+	}
+	edited := strings.Join(lines, "\n")
+	if edited == string(data) {
+		return false, nil
+	}
+	return true, ioutil.WriteFile(path, []byte(edited), 0644)
+}
+
+//insert adds the import and instance of each package not already registered
+func insert(lines []string, packages []Package, kotlin bool) ([]string, error) {
+	anchor, after := findAnchor(lines, kotlin)
+	if anchor < 0 {
+		return lines, ErrUnrecognised
+	}
+	indent := instanceIndent(lines, anchor, after)
+	imports := make([]string, 0, len(packages))
+	instances := make([]string, 0, len(packages))
+	for _, p := range packages {
+		if registered(lines, p, kotlin) {
+			continue
+		}
+		imports = append(imports, importLine(p, kotlin)+" "+Marker)
+		instances = append(instances, indent+instanceLine(lines[anchor], p, kotlin)+" "+Marker)
+	}
+	if len(instances) == 0 {
+		return lines, nil
+	}
+	at := anchor
+	if after {
+		at++
+	}
+	lines = splice(lines, at, instances)
+	return splice(lines, importIndex(lines), imports), nil
+}
+
+//findAnchor returns the line of getPackages new instances are added
+//relative to, and whether they go after it rather than before
+func findAnchor(lines []string, kotlin bool) (int, bool) {
+	inGetPackages := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.Contains(line, "getPackages") {
+			inGetPackages = true
+		}
+		if !inGetPackages {
+			continue
+		}
+		switch {
+		case kotlin && strings.HasSuffix(trimmed, "PackageList(this).packages.apply {"):
+			return i, true
+		case kotlin && trimmed == "return packages":
+			return i, false
+		case !kotlin && trimmed == "return packages;":
+			return i, false
+		case !kotlin && strings.HasSuffix(trimmed, "asList("):
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+//instanceIndent matches the indentation of the statements around the anchor
+func instanceIndent(lines []string, anchor int, after bool) string {
+	indent := leadingSpace(lines[anchor])
+	if !after {
+		return indent
+	}
+	if anchor+1 < len(lines) {
+		next := leadingSpace(lines[anchor+1])
+		if len(next) > len(indent) {
+			return next
+		}
+	}
+	if strings.Contains(indent, "\t") {
+		return indent + "\t"
+	}
+	return indent + "    "
+}
+
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+//registered identifies whether p is already created by a line written by hand
+func registered(lines []string, p Package, kotlin bool) bool {
+	instance := "new " + p.simpleName() + "("
+	if kotlin {
+		instance = p.simpleName() + "("
+	}
+	for _, line := range lines {
+		if strings.Contains(line, instance) && !strings.HasPrefix(strings.TrimSpace(line), "//") {
+			return true
+		}
+	}
+	return false
+}
+
+func importLine(p Package, kotlin bool) string {
+	if kotlin {
+		return "import " + p.Class
+	}
+	return "import " + p.Class + ";"
+}
+
+//instanceLine creates p in the style of the anchor, either adding to the
+//packages list or as an element of an asList or apply block
+func instanceLine(anchor string, p Package, kotlin bool) string {
+	trimmed := strings.TrimSpace(anchor)
+	switch {
+	case kotlin && strings.HasSuffix(trimmed, "{"):
+		return "add(" + p.simpleName() + "())"
+	case kotlin:
+		return "packages.add(" + p.simpleName() + "())"
+	case strings.HasSuffix(trimmed, "asList("):
+		return "new " + p.simpleName() + "(),"
+	}
+	return "packages.add(new " + p.simpleName() + "());"
+}
+
+//importIndex is the line new imports are added at, after the last import
+//or otherwise after the package declaration
+func importIndex(lines []string) int {
+	at := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "import ") || (at < 0 && strings.HasPrefix(line, "package ")) {
+			at = i
+		}
+	}
+	return at + 1
+}
+
+//splice inserts add into lines at index i
+func splice(lines []string, i int, add []string) []string {
+	if len(add) == 0 {
+		return lines
+	}
+	spliced := make([]string, 0, len(lines)+len(add))
+	spliced = append(spliced, lines[:i]...)
+	spliced = append(spliced, add...)
+	return append(spliced, lines[i:]...)
+}
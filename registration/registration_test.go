@@ -0,0 +1,230 @@
+package registration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const javaApplication = `package com.example;
+
+import com.facebook.react.PackageList;
+import com.facebook.react.ReactPackage;
+import java.util.List;
+
+public class MainApplication extends Application implements ReactApplication {
+  private final ReactNativeHost mReactNativeHost = new ReactNativeHost(this) {
+    @Override
+    protected List<ReactPackage> getPackages() {
+      List<ReactPackage> packages = new PackageList(this).getPackages();
+      // packages.add(new MyReactNativePackage());
+      return packages;
+    }
+  };
+}
+`
+
+const kotlinApplication = `package com.example
+
+import com.facebook.react.PackageList
+
+class MainApplication : Application(), ReactApplication {
+  override val reactNativeHost: ReactNativeHost =
+      object : DefaultReactNativeHost(this) {
+        override fun getPackages(): List<ReactPackage> =
+            PackageList(this).packages.apply {
+              // add(MyReactNativePackage())
+            }
+      }
+}
+`
+
+var hello = Package{Class: "com.reactgohybrid.bridge.hello.HelloPackage"}
+
+//writeApplication writes the MainApplication name with content under root
+func writeApplication(root string, name string, content string) string {
+	os.MkdirAll(filepath.Join(root, "com/example"), 0777)
+	path := filepath.Join(root, "com/example", name)
+	ioutil.WriteFile(path, []byte(content), 0666)
+	return path
+}
+
+func read(path string) string {
+	data, _ := ioutil.ReadFile(path)
+	return string(data)
+}
+
+func TestFind(t *testing.T) {
+	Convey("Given an app with a Kotlin MainApplication", t, func() {
+		path := writeApplication(t.TempDir(), "MainApplication.kt", kotlinApplication)
+		Convey("When it is searched for from the source root", func() {
+			found, err := Find(filepath.Dir(filepath.Dir(filepath.Dir(path))))
+			Convey("Then it is found", func() {
+				So(err, ShouldBeNil)
+				So(found, ShouldEqual, path)
+			})
+		})
+		Convey("When a folder without it is searched", func() {
+			_, err := Find(filepath.Dir(path) + "/missing")
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestRegister(t *testing.T) {
+	Convey("Given a Java MainApplication", t, func() {
+		path := writeApplication(t.TempDir(), "MainApplication.java", javaApplication)
+		Convey("When a package is registered", func() {
+			changed, err := Register(path, []Package{hello})
+			content := read(path)
+			Convey("Then the file is changed", func() {
+				So(err, ShouldBeNil)
+				So(changed, ShouldBeTrue)
+			})
+			Convey("And the import follows the existing imports", func() {
+				So(content, ShouldContainSubstring,
+					"import java.util.List;\nimport com.reactgohybrid.bridge.hello.HelloPackage; "+Marker+"\n")
+			})
+			Convey("And the instance is added before packages are returned", func() {
+				So(content, ShouldContainSubstring,
+					"      packages.add(new HelloPackage()); "+Marker+"\n      return packages;")
+			})
+			Convey("When it is registered again", func() {
+				changed, err = Register(path, []Package{hello})
+				Convey("Then the file is unchanged", func() {
+					So(err, ShouldBeNil)
+					So(changed, ShouldBeFalse)
+					So(read(path), ShouldEqual, content)
+				})
+			})
+			Convey("When it is no longer produced", func() {
+				changed, err = Register(path, []Package{})
+				Convey("Then its lines are removed", func() {
+					So(changed, ShouldBeTrue)
+					So(read(path), ShouldEqual, javaApplication)
+				})
+			})
+			Convey("When it is unregistered", func() {
+				changed, err = Unregister(path)
+				Convey("Then the original file is restored", func() {
+					So(err, ShouldBeNil)
+					So(changed, ShouldBeTrue)
+					So(read(path), ShouldEqual, javaApplication)
+				})
+			})
+		})
+	})
+	Convey("Given a Kotlin MainApplication", t, func() {
+		path := writeApplication(t.TempDir(), "MainApplication.kt", kotlinApplication)
+		Convey("When a package is registered", func() {
+			_, err := Register(path, []Package{hello})
+			content := read(path)
+			Convey("Then the import has no semicolon", func() {
+				So(err, ShouldBeNil)
+				So(content, ShouldContainSubstring, "import com.reactgohybrid.bridge.hello.HelloPackage "+Marker+"\n")
+			})
+			Convey("And the instance is added within the apply block", func() {
+				So(content, ShouldContainSubstring,
+					"packages.apply {\n              add(HelloPackage()) "+Marker+"\n")
+			})
+		})
+	})
+	Convey("Given a MainApplication registering the package by hand", t, func() {
+		manual := strings.Replace(javaApplication, "      return packages;",
+			"      packages.add(new HelloPackage());\n      return packages;", 1)
+		path := writeApplication(t.TempDir(), "MainApplication.java", manual)
+		Convey("When the package is registered", func() {
+			changed, err := Register(path, []Package{hello})
+			Convey("Then the file is left alone", func() {
+				So(err, ShouldBeNil)
+				So(changed, ShouldBeFalse)
+			})
+		})
+	})
+	Convey("Given a MainApplication with an unknown structure", t, func() {
+		unknown := strings.Replace(javaApplication, "return packages;", "return buildPackages();", 1)
+		path := writeApplication(t.TempDir(), "MainApplication.java", unknown)
+		Convey("When a package is registered", func() {
+			changed, err := Register(path, []Package{hello})
+			Convey("Then it is not recognised and the file is left alone", func() {
+				So(err, ShouldEqual, ErrUnrecognised)
+				So(changed, ShouldBeFalse)
+				So(read(path), ShouldEqual, unknown)
+			})
+		})
+	})
+}
+
+func TestWireGradle(t *testing.T) {
+	Convey("Given an app module with a Groovy build file", t, func() {
+		dir := filepath.Join(t.TempDir(), "app")
+		os.MkdirAll(dir, 0777)
+		original := "apply plugin: \"com.android.application\"\n"
+		ioutil.WriteFile(filepath.Join(dir, "build.gradle"), []byte(original), 0666)
+		Convey("When the bridge is wired in", func() {
+			changed, err := WireGradle(dir, filepath.Join(dir, "libs/gobridge.aar"),
+				[]string{filepath.Join(dir, "src/main/java"), filepath.Join(dir, "src/bridge/java")})
+			script := read(filepath.Join(dir, GradleScript))
+			Convey("Then the files are changed", func() {
+				So(err, ShouldBeNil)
+				So(changed, ShouldBeTrue)
+			})
+			Convey("And bridge.gradle declares the aar", func() {
+				So(script, ShouldContainSubstring, `implementation files("libs/gobridge.aar")`)
+			})
+			Convey("And only source folders other than the default are added", func() {
+				So(script, ShouldContainSubstring, `java.srcDirs += ["src/bridge/java"]`)
+				So(script, ShouldNotContainSubstring, `"src/main/java"`)
+			})
+			Convey("And the build file applies it", func() {
+				So(read(filepath.Join(dir, "build.gradle")), ShouldEqual,
+					original+"apply from: \"bridge.gradle\" "+Marker+"\n")
+			})
+			Convey("When it is wired in again", func() {
+				changed, err = WireGradle(dir, filepath.Join(dir, "libs/gobridge.aar"),
+					[]string{filepath.Join(dir, "src/main/java"), filepath.Join(dir, "src/bridge/java")})
+				Convey("Then nothing changes", func() {
+					So(err, ShouldBeNil)
+					So(changed, ShouldBeFalse)
+				})
+			})
+			Convey("When it is unwired", func() {
+				changed, err = UnwireGradle(dir)
+				Convey("Then the build file is restored and bridge.gradle removed", func() {
+					So(err, ShouldBeNil)
+					So(changed, ShouldBeTrue)
+					So(read(filepath.Join(dir, "build.gradle")), ShouldEqual, original)
+					_, err = os.Stat(filepath.Join(dir, GradleScript))
+					So(os.IsNotExist(err), ShouldBeTrue)
+				})
+			})
+		})
+	})
+	Convey("Given an app module with a Kotlin build file", t, func() {
+		dir := filepath.Join(t.TempDir(), "app")
+		os.MkdirAll(dir, 0777)
+		ioutil.WriteFile(filepath.Join(dir, "build.gradle.kts"), []byte("plugins {}"), 0666)
+		Convey("When the bridge is wired in", func() {
+			_, err := WireGradle(dir, filepath.Join(dir, "libs/gobridge.aar"), nil)
+			Convey("Then the Kotlin apply syntax is used", func() {
+				So(err, ShouldBeNil)
+				So(read(filepath.Join(dir, "build.gradle.kts")), ShouldEqual,
+					"plugins {}\napply(from = \"bridge.gradle\") "+Marker+"\n")
+			})
+		})
+	})
+	Convey("Given an app module without a build file", t, func() {
+		Convey("When the bridge is wired in", func() {
+			_, err := WireGradle(filepath.Join(t.TempDir(), "missing"), "libs/gobridge.aar", nil)
+			Convey("Then an error is returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
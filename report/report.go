@@ -0,0 +1,83 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+//Version is the version of the report written by this tool
+const Version = 1
+
+//Report is what a generation run did, for build scripts and dashboards
+type Report struct {
+	Version int `json:"version"`
+	//FailedStage is the stage the run stopped at, parsing, validation or
+	//writing, when one failed
+	FailedStage string    `json:"failedStage,omitempty"`
+	Errors      int       `json:"errors"`
+	Warnings    int       `json:"warnings"`
+	Packages    []Package `json:"packages"`
+	//Files holds the files shared by every package, such as the executors
+	//and the aggregate package
+	Files []File `json:"files,omitempty"`
+}
+
+//Package statuses
+const (
+	//StatusGenerated is a package whose files were written
+	StatusGenerated = "generated"
+	//StatusUnchanged is a package skipped as the cache holds it unchanged
+	StatusUnchanged = "unchanged"
+	//StatusFailed is a package whose files could not all be written
+	StatusFailed = "failed"
+	//StatusSkipped is a package not generated, as it has nothing to bridge
+	//or the run stopped before writing it
+	StatusSkipped = "skipped"
+)
+
+//Package is what the run did with one Go package
+type Package struct {
+	Name       string     `json:"name"`
+	ImportPath string     `json:"importPath,omitempty"`
+	Status     string     `json:"status"`
+	Functions  []Function `json:"functions"`
+	Skipped    []Skipped  `json:"skipped,omitempty"`
+	Files      []File     `json:"files,omitempty"`
+}
+
+//Function is a bridged Go function, with the name and TypeScript signature
+//JS calls it by and the thread it runs on
+type Function struct {
+	Name      string `json:"name"`
+	JSName    string `json:"jsName"`
+	Signature string `json:"signature"`
+	Sync      bool   `json:"sync,omitempty"`
+	Thread    string `json:"thread"`
+}
+
+//Skipped is a symbol left out of the bridge, with the code and message of
+//the diagnostic reporting it
+type Skipped struct {
+	Symbol string `json:"symbol"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+//File is an output file, and whether the run created or changed it, or
+//left it unchanged
+type File struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+//Write writes the report to w as indented JSON, leaving TypeScript
+//signatures such as Promise<string> unescaped
+func (r Report) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWrite(t *testing.T) {
+	Convey("Given the report of a run writing one package", t, func() {
+		r := Report{
+			Version:  Version,
+			Warnings: 1,
+			Packages: []Package{Package{
+				Name:       "jobs",
+				ImportPath: "example.com/jobs",
+				Status:     StatusGenerated,
+				Functions: []Function{
+					Function{Name: "Start", JSName: "start", Signature: "start(id: string): Promise<void>", Thread: "background"},
+					Function{Name: "Running", JSName: "running", Signature: "running(): boolean", Sync: true, Thread: "inline"},
+				},
+				Skipped: []Skipped{Skipped{Symbol: "Stream", Code: "RGN100", Reason: "unsupported type", File: "jobs.go", Line: 12, Column: 6}},
+				Files: []File{
+					File{Path: "java/Jobs.java", Status: "created"},
+					File{Path: "java/JobsModule.java", Status: "changed"},
+					File{Path: "js/jobs.js", Status: "unchanged"},
+				},
+			}},
+		}
+		Convey("When it is written", func() {
+			var out strings.Builder
+			So(r.Write(&out), ShouldBeNil)
+			Convey("Then it is indented JSON with the TypeScript signatures unescaped", func() {
+				So(out.String(), ShouldEqual, `{
+  "version": 1,
+  "errors": 0,
+  "warnings": 1,
+  "packages": [
+    {
+      "name": "jobs",
+      "importPath": "example.com/jobs",
+      "status": "generated",
+      "functions": [
+        {
+          "name": "Start",
+          "jsName": "start",
+          "signature": "start(id: string): Promise<void>",
+          "thread": "background"
+        },
+        {
+          "name": "Running",
+          "jsName": "running",
+          "signature": "running(): boolean",
+          "sync": true,
+          "thread": "inline"
+        }
+      ],
+      "skipped": [
+        {
+          "symbol": "Stream",
+          "code": "RGN100",
+          "reason": "unsupported type",
+          "file": "jobs.go",
+          "line": 12,
+          "column": 6
+        }
+      ],
+      "files": [
+        {
+          "path": "java/Jobs.java",
+          "status": "created"
+        },
+        {
+          "path": "java/JobsModule.java",
+          "status": "changed"
+        },
+        {
+          "path": "js/jobs.js",
+          "status": "unchanged"
+        }
+      ]
+    }
+  ]
+}
+`)
+			})
+			Convey("And it reads back as the same report", func() {
+				var read Report
+				So(json.Unmarshal([]byte(out.String()), &read), ShouldBeNil)
+				So(read, ShouldResemble, r)
+			})
+		})
+	})
+	Convey("Given the report of a run that failed validation", t, func() {
+		r := Report{Version: Version, FailedStage: "validation", Errors: 2, Packages: []Package{
+			Package{Name: "jobs", Status: StatusSkipped, Functions: []Function{}},
+		}, Files: []File{File{Path: "java/GoExecutors.java", Status: "unchanged"}}}
+		Convey("When it is written", func() {
+			var out strings.Builder
+			So(r.Write(&out), ShouldBeNil)
+			var fields map[string]json.RawMessage
+			So(json.Unmarshal([]byte(out.String()), &fields), ShouldBeNil)
+			Convey("Then the failed stage and the shared files are listed", func() {
+				So(string(fields["failedStage"]), ShouldEqual, `"validation"`)
+				So(string(fields["errors"]), ShouldEqual, "2")
+				So(out.String(), ShouldContainSubstring, `"path": "java/GoExecutors.java",`)
+			})
+			Convey("And the optional fields of the package are left out", func() {
+				var packages []map[string]json.RawMessage
+				So(json.Unmarshal(fields["packages"], &packages), ShouldBeNil)
+				So(packages, ShouldHaveLength, 1)
+				So(packages[0], ShouldNotContainKey, "importPath")
+				So(packages[0], ShouldNotContainKey, "skipped")
+				So(packages[0], ShouldNotContainKey, "files")
+				So(string(packages[0]["functions"]), ShouldEqual, "[]")
+			})
+		})
+	})
+}
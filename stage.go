@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/steve-winter/reactgonative/diagnostics"
+)
+
+//Exit codes. Failures of the parse, validation and write stages each exit
+//with their own code, so CI can tell them apart from each other and from
+//later failures such as a breaking API change or a failed build.
+const (
+	exitOK       = 0
+	exitFailed   = 1
+	exitUsage    = 2
+	exitParse    = 3
+	exitValidate = 4
+	exitWrite    = 5
+)
+
+//Stage is a step of the pipeline that stops the run when it fails
+type Stage int
+
+//Stages, in the order they run
+const (
+	StageParse Stage = iota + 1
+	StageValidate
+	StageWrite
+)
+
+func (s Stage) String() string {
+	switch s {
+	case StageParse:
+		return "parsing"
+	case StageValidate:
+		return "validation"
+	case StageWrite:
+		return "writing"
+	}
+	return fmt.Sprintf("stage %d", int(s))
+}
+
+//ExitCode is the code the tool exits with when s fails
+func (s Stage) ExitCode() int {
+	switch s {
+	case StageParse:
+		return exitParse
+	case StageValidate:
+		return exitValidate
+	case StageWrite:
+		return exitWrite
+	}
+	return exitFailed
+}
+
+//Error is returned when a stage fails, having reported errors, or warnings
+//under strict. The stages depending on it are skipped. The diagnostics of
+//the run explain the failure.
+type Error struct {
+	Stage    Stage
+	Errors   int
+	Warnings int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s failed with %d errors and %d warnings", e.Stage, e.Errors, e.Warnings)
+}
+
+//stageFailed returns the *Error for stage when the diagnostics it reported
+//fail the run, and otherwise nil
+func stageFailed(stage Stage, diags *diagnostics.List, strict bool) error {
+	if !diags.Failed(strict) {
+		return nil
+	}
+	return &Error{Stage: stage, Errors: diags.Count(diagnostics.Error), Warnings: diags.Count(diagnostics.Warning)}
+}
+
+//exitCode is the code to exit with after a run returning err and
+//reporting diags
+func exitCode(err error, diags *diagnostics.List, strict bool) int {
+	var stage *Error
+	if errors.As(err, &stage) {
+		return stage.Stage.ExitCode()
+	}
+	if err != nil || diags.Failed(strict) {
+		return exitFailed
+	}
+	return exitOK
+}
@@ -0,0 +1,130 @@
+package com.reactgohybrid.bridge.basic;
+
+import com.facebook.react.bridge.ReactApplicationContext;
+import com.facebook.react.bridge.Promise;
+import com.facebook.react.bridge.ReactContextBaseJavaModule;
+import com.facebook.react.bridge.ReactMethod;
+import com.facebook.react.bridge.ReactMethod;
+import com.facebook.react.bridge.Arguments;
+import com.facebook.react.bridge.WritableMap;
+import java.util.HashMap;
+import java.util.Map;
+import java.util.concurrent.ExecutorService;
+import java.util.concurrent.Executors;
+import basic.Basic;
+
+public class BasicModule extends ReactContextBaseJavaModule {
+
+	private final ExecutorService executor = Executors.newSingleThreadExecutor();
+
+	public BasicModule(ReactApplicationContext reactContext) {
+		super(reactContext);
+	}
+
+	@Override
+	public String getName() {
+		return "BasicModule";
+	}
+
+	@Override
+	public Map<String, Object> getConstants() {
+		final Map<String, Object> constants = new HashMap<>();
+		constants.put("MaxItems", Basic.MaxItems);
+		constants.put("Version", Basic.Version);
+		constants.put("Ratio", Basic.Ratio);
+		return constants;
+	}
+
+	@ReactMethod
+	public void greet(String name, Promise promise) {
+		try {
+			String returnParam1 = Basic.greet(name);
+			promise.resolve(returnParam1);
+		} catch(Exception e) {
+			rejectGoError(promise, e);
+		}
+	}
+
+	@ReactMethod
+	public void repeat(String name, double count, Promise promise) {
+		try {
+			String returnParam1 = Basic.repeat(name, (long) count);
+			promise.resolve(returnParam1);
+		} catch(Exception e) {
+			rejectGoError(promise, e);
+		}
+	}
+
+	@ReactMethod
+	public void lookup(String name, Promise promise) {
+		executor.execute(() -> {
+			try {
+				long returnParam1 = Basic.lookup(name);
+				promise.resolve((double) returnParam1);
+			} catch(Exception e) {
+				rejectGoError(promise, e);
+			}
+		});
+	}
+
+	@ReactMethod(isBlockingSynchronousMethod = true)
+	public boolean enabled() {
+		boolean returnParam1 = Basic.enabled();
+		return returnParam1;
+	}
+
+	@ReactMethod(isBlockingSynchronousMethod = true)
+	public double count() {
+		long returnParam1 = Basic.count();
+		return (double) returnParam1;
+	}
+
+	public static String levelToString(long value) {
+		if (value == 0L) {
+			return "Debug";
+		}
+		if (value == 1L) {
+			return "Info";
+		}
+		if (value == 2L) {
+			return "Error";
+		}
+		return null;
+	}
+
+	public static Long levelFromString(String value) {
+		if ("Debug".equals(value)) {
+			return 0L;
+		}
+		if ("Info".equals(value)) {
+			return 1L;
+		}
+		if ("Error".equals(value)) {
+			return 2L;
+		}
+		return null;
+	}
+
+	private static void rejectGoError(Promise promise, Exception e) {
+		final String message = e.getMessage() == null ? "" : e.getMessage();
+		if (message.equals("name not found") || message.endsWith(": name not found")) {
+			final WritableMap userInfo = Arguments.createMap();
+			userInfo.putString("goError", "ErrNotFound");
+			promise.reject("E_NOT_FOUND", message, e, userInfo);
+			return;
+		}
+		if (message.startsWith("limit reached: ")) {
+			final WritableMap userInfo = Arguments.createMap();
+			userInfo.putString("goError", "LimitError");
+			promise.reject("E_LIMIT", message, e, userInfo);
+			return;
+		}
+		promise.reject("Error", message, e);
+	}
+
+	@Override
+	public void onCatalystInstanceDestroy() {
+		executor.shutdown();
+	}
+
+}
@@ -0,0 +1,23 @@
+export const MaxItems: 16;
+export const Version: "1.0";
+export const Ratio: number;
+
+export type Level = 'Debug' | 'Info' | 'Error';
+export const Level: {
+	Debug: 'Debug';
+	Info: 'Info';
+	Error: 'Error';
+};
+
+export type ErrorCode = 'E_NOT_FOUND' | 'E_LIMIT' | 'E_UNKNOWN_ENUM' | 'Error';
+
+export interface GoError extends Error {
+	code: ErrorCode;
+	userInfo?: Record<string, string> | null;
+}
+
+export function greet(name: string): Promise<string>;
+export function repeat(name: string, count: number): Promise<string>;
+export function lookup(name: string): Promise<number>;
+export function enabled(): boolean;
+export function count(): number;
@@ -0,0 +1,35 @@
+import { NativeModules } from 'react-native';
+
+const BasicModule = NativeModules.BasicModule;
+
+export const MaxItems = BasicModule.MaxItems;
+export const Version = BasicModule.Version;
+export const Ratio = BasicModule.Ratio;
+
+export const Level = Object.freeze({
+	Debug: 'Debug',
+	Info: 'Info',
+	Error: 'Error',
+});
+
+export function greet(name) {
+	return BasicModule.greet(name);
+}
+
+export function repeat(name, count) {
+	return BasicModule.repeat(name, count);
+}
+
+export function lookup(name) {
+	return BasicModule.lookup(name);
+}
+
+export function enabled() {
+	return BasicModule.enabled();
+}
+
+export function count() {
+	return BasicModule.count();
+}
+
+export default BasicModule;
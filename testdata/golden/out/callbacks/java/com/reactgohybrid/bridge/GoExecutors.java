@@ -0,0 +1,9 @@
+package com.reactgohybrid.bridge;
+
+import java.util.concurrent.ExecutorService;
+import java.util.concurrent.Executors;
+
+public class GoExecutors {
+
+	public static final ExecutorService POOL = Executors.newFixedThreadPool(Runtime.getRuntime().availableProcessors());
+}
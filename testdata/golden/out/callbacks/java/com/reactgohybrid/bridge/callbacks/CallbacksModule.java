@@ -0,0 +1,49 @@
+package com.reactgohybrid.bridge.callbacks;
+
+import com.facebook.react.bridge.ReactApplicationContext;
+import com.facebook.react.bridge.Promise;
+import com.facebook.react.bridge.ReactContextBaseJavaModule;
+import com.facebook.react.bridge.ReactMethod;
+import com.facebook.react.bridge.ReactMethod;
+import com.reactgohybrid.bridge.GoExecutors;
+import callbacks.Callbacks;
+
+public class CallbacksModule extends ReactContextBaseJavaModule {
+
+	public CallbacksModule(ReactApplicationContext reactContext) {
+		super(reactContext);
+	}
+
+	@Override
+	public String getName() {
+		return "CallbacksModule";
+	}
+
+	@ReactMethod
+	public void download(String url, Promise promise) {
+		GoExecutors.POOL.execute(() -> {
+			try {
+				Callbacks.download(url, new ProgressEmitter(getReactApplicationContext()));
+				promise.resolve(null);
+			} catch(Exception e) {
+				rejectGoError(promise, e);
+			}
+		});
+	}
+
+	private static void rejectGoError(Promise promise, Exception e) {
+		final String message = e.getMessage() == null ? "" : e.getMessage();
+		promise.reject("Error", message, e);
+	}
+
+	@ReactMethod
+	public void addListener(String eventName) {
+		//Required by NativeEventEmitter
+	}
+
+	@ReactMethod
+	public void removeListeners(double count) {
+		//Required by NativeEventEmitter
+	}
+
+}
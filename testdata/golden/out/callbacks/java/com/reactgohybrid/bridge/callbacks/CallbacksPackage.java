@@ -0,0 +1,31 @@
+package com.reactgohybrid.bridge.callbacks;
+
+import com.facebook.react.ReactPackage;
+import com.facebook.react.bridge.JavaScriptModule;
+import com.facebook.react.bridge.NativeModule;
+import com.facebook.react.bridge.ReactApplicationContext;
+import com.facebook.react.uimanager.ViewManager;
+import java.util.ArrayList;
+import java.util.Collections;
+import java.util.List;
+
+public class CallbacksPackage implements ReactPackage {
+
+	@Override
+	public List<NativeModule> createNativeModules(ReactApplicationContext reactContext) {
+		List<NativeModule> modules = new ArrayList<>();
+		modules.add(new CallbacksModule(reactContext));
+		return modules;
+	}
+
+	@Override
+	public List <Class<? extends JavaScriptModule>> createJSModules() {
+		return Collections.emptyList();
+	}
+
+	@Override
+	public List<ViewManager> createViewManagers(ReactApplicationContext reactContext) {
+		return Collections.emptyList();
+	}
+
+}
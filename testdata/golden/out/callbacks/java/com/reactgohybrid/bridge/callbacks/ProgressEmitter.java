@@ -0,0 +1,35 @@
+package com.reactgohybrid.bridge.callbacks;
+
+import com.facebook.react.bridge.Arguments;
+import com.facebook.react.bridge.ReactApplicationContext;
+import com.facebook.react.bridge.WritableMap;
+import com.facebook.react.modules.core.DeviceEventManagerModule;
+import callbacks.Progress;
+
+public class ProgressEmitter implements Progress {
+
+	private final ReactApplicationContext reactContext;
+
+	public ProgressEmitter(ReactApplicationContext reactContext) {
+		this.reactContext = reactContext;
+	}
+
+	@Override
+	public void onProgress(long done, long total, String file) {
+		WritableMap event = Arguments.createMap();
+		event.putDouble("done", done);
+		event.putDouble("total", total);
+		event.putString("file", file);
+		emit("CallbacksModule.Progress.OnProgress", event);
+	}
+
+	@Override
+	public void onComplete() {
+		WritableMap event = Arguments.createMap();
+		emit("CallbacksModule.Progress.OnComplete", event);
+	}
+
+	private void emit(String eventName, WritableMap event) {
+		reactContext.getJSModule(DeviceEventManagerModule.RCTDeviceEventEmitter.class).emit(eventName, event);
+	}
+}
@@ -0,0 +1,26 @@
+import { EmitterSubscription } from 'react-native';
+
+export interface ProgressOnProgressEvent {
+	done: number;
+	total: number;
+	file: string;
+}
+
+export type ProgressOnCompleteEvent = Record<string, never>;
+
+export const ProgressEvents: {
+	OnProgress: 'CallbacksModule.Progress.OnProgress';
+	OnComplete: 'CallbacksModule.Progress.OnComplete';
+};
+
+export function addProgressOnProgressListener(listener: (event: ProgressOnProgressEvent) => void): EmitterSubscription;
+export function addProgressOnCompleteListener(listener: (event: ProgressOnCompleteEvent) => void): EmitterSubscription;
+
+export type ErrorCode = 'Error';
+
+export interface GoError extends Error {
+	code: ErrorCode;
+	userInfo?: Record<string, string> | null;
+}
+
+export function download(url: string): Promise<void>;
@@ -0,0 +1,23 @@
+import { NativeModules, NativeEventEmitter } from 'react-native';
+
+const CallbacksModule = NativeModules.CallbacksModule;
+const emitter = new NativeEventEmitter(CallbacksModule);
+
+export const ProgressEvents = {
+	OnProgress: 'CallbacksModule.Progress.OnProgress',
+	OnComplete: 'CallbacksModule.Progress.OnComplete',
+};
+
+export function addProgressOnProgressListener(listener) {
+	return emitter.addListener(ProgressEvents.OnProgress, listener);
+}
+
+export function addProgressOnCompleteListener(listener) {
+	return emitter.addListener(ProgressEvents.OnComplete, listener);
+}
+
+export function download(url) {
+	return CallbacksModule.download(url);
+}
+
+export default CallbacksModule;
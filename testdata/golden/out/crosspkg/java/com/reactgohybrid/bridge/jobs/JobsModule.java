@@ -0,0 +1,36 @@
+package com.reactgohybrid.bridge.jobs;
+
+import com.facebook.react.bridge.ReactApplicationContext;
+import com.facebook.react.bridge.Promise;
+import com.facebook.react.bridge.ReactContextBaseJavaModule;
+import com.facebook.react.bridge.ReactMethod;
+import com.facebook.react.bridge.ReactMethod;
+import jobs.Jobs;
+
+public class JobsModule extends ReactContextBaseJavaModule {
+
+	public JobsModule(ReactApplicationContext reactContext) {
+		super(reactContext);
+	}
+
+	@Override
+	public String getName() {
+		return "JobsModule";
+	}
+
+	@ReactMethod
+	public void start(String id, Promise promise) {
+		try {
+			Jobs.start(id);
+			promise.resolve(null);
+		} catch(Exception e) {
+			rejectGoError(promise, e);
+		}
+	}
+
+	private static void rejectGoError(Promise promise, Exception e) {
+		final String message = e.getMessage() == null ? "" : e.getMessage();
+		promise.reject("Error", message, e);
+	}
+
+}
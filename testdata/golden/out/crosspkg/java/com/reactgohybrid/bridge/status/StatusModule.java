@@ -0,0 +1,41 @@
+package com.reactgohybrid.bridge.status;
+
+import com.facebook.react.bridge.ReactApplicationContext;
+import com.facebook.react.bridge.Promise;
+import com.facebook.react.bridge.ReactContextBaseJavaModule;
+import com.facebook.react.bridge.ReactMethod;
+import com.facebook.react.bridge.ReactMethod;
+import status.Status;
+
+public class StatusModule extends ReactContextBaseJavaModule {
+
+	public StatusModule(ReactApplicationContext reactContext) {
+		super(reactContext);
+	}
+
+	@Override
+	public String getName() {
+		return "StatusModule";
+	}
+
+	public static String statusToString(long value) {
+		if (value == 0L) {
+			return "Queued";
+		}
+		if (value == 1L) {
+			return "Running";
+		}
+		return null;
+	}
+
+	public static Long statusFromString(String value) {
+		if ("Queued".equals(value)) {
+			return 0L;
+		}
+		if ("Running".equals(value)) {
+			return 1L;
+		}
+		return null;
+	}
+
+}
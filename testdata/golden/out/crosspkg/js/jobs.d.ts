@@ -0,0 +1,8 @@
+export type ErrorCode = 'Error';
+
+export interface GoError extends Error {
+	code: ErrorCode;
+	userInfo?: Record<string, string> | null;
+}
+
+export function start(id: string): Promise<void>;
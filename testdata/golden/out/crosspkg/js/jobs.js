@@ -0,0 +1,9 @@
+import { NativeModules } from 'react-native';
+
+const JobsModule = NativeModules.JobsModule;
+
+export function start(id) {
+	return JobsModule.start(id);
+}
+
+export default JobsModule;
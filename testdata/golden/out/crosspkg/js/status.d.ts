@@ -0,0 +1,6 @@
+export type Status = 'Queued' | 'Running';
+export const Status: {
+	Queued: 'Queued';
+	Running: 'Running';
+};
+
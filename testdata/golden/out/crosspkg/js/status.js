@@ -0,0 +1,10 @@
+import { NativeModules } from 'react-native';
+
+const StatusModule = NativeModules.StatusModule;
+
+export const Status = Object.freeze({
+	Queued: 'Queued',
+	Running: 'Running',
+});
+
+export default StatusModule;
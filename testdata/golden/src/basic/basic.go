@@ -0,0 +1,66 @@
+//Package basic covers functions, constants, enums and errors
+package basic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//MaxItems is the most items a list holds
+const MaxItems = 1 << 4
+
+const (
+	Version = "1.0"
+	Ratio   = 1.5
+)
+
+//Level is how much detail is logged
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelError
+)
+
+//ErrNotFound is returned for unknown names
+var ErrNotFound = errors.New("name not found")
+
+//LimitError is returned when a limit is reached
+type LimitError struct{ Limit int }
+
+func (e *LimitError) Error() string { return fmt.Sprintf("limit reached: %d", e.Limit) }
+
+//Code identifies limit errors
+func (e *LimitError) Code() string { return "E_LIMIT" }
+
+//Greet says hello
+func Greet(name string) string { return "Hello, " + name }
+
+//Repeat says hello count times
+func Repeat(name string, count int) string { return strings.Repeat(Greet(name), count) }
+
+//Lookup finds the age of name
+//reactgonative:thread background
+func Lookup(name string) (int64, error) { return 0, ErrNotFound }
+
+//Enabled reports whether logging is on
+//reactgonative:sync
+func Enabled() bool { return true }
+
+//SetLevel changes the log level
+func SetLevel(l Level) error { return nil }
+
+//CurrentLevel returns the log level
+func CurrentLevel() Level { return LevelInfo }
+
+//reactgonative:ignore
+func Hidden() {}
+
+//Pair is not bound, gomobile binds a single result
+func Pair() (int, string) { return 0, "" }
+
+//Count is how many names are known
+//reactgonative:sync
+func Count() int { return 0 }
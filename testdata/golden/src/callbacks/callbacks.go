@@ -0,0 +1,12 @@
+//Package callbacks covers interfaces implemented by the bridge
+package callbacks
+
+//Progress receives updates from Download
+type Progress interface {
+	OnProgress(done int64, total int64, file string)
+	OnComplete()
+}
+
+//Download fetches url, reporting progress
+//reactgonative:thread pool
+func Download(url string, p Progress) error { return nil }
@@ -0,0 +1,14 @@
+//Package jobs uses a type from another bound package
+package jobs
+
+import "github.com/steve-winter/reactgonative/testdata/golden/src/crosspkg/status"
+
+//Start queues job id
+func Start(id string) error { return nil }
+
+//State returns the status of job id, not bound as gomobile does not bind
+//named integer types
+func State(id string) (status.Status, error) { return status.StatusQueued, nil }
+
+//SetState changes the status of job id
+func SetState(id string, s status.Status) error { return nil }
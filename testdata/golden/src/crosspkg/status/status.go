@@ -0,0 +1,10 @@
+//Package status declares a type used by another bound package
+package status
+
+//Status is the state of a job
+type Status int
+
+const (
+	StatusQueued Status = iota
+	StatusRunning
+)
@@ -0,0 +1,14 @@
+package types
+
+import "go/token"
+
+//GoConstant represents an exported Go constant.
+//T is the declared type, or the default type of an untyped constant.
+//Value is the exact Go representation of the constant's value.
+type GoConstant struct {
+	Name       string
+	T          string
+	Value      string
+	Directives GoDirectives
+	Pos        token.Position
+}
@@ -0,0 +1,30 @@
+package types
+
+//DirectivePrefix is the comment prefix identifying a reactgonative directive,
+//for example //reactgonative:ignore
+const DirectivePrefix = "//reactgonative:"
+
+//Threads a bridged Go call can run on. ThreadInline runs on the React Native
+//native modules thread, ThreadBackground on a single thread owned by the
+//module, and ThreadPool on a pool shared by every module.
+const (
+	ThreadInline     = "inline"
+	ThreadBackground = "background"
+	ThreadPool       = "pool"
+)
+
+//IsThread identifies whether t is one of the supported threads
+func IsThread(t string) bool {
+	return t == ThreadInline || t == ThreadBackground || t == ThreadPool
+}
+
+//GoDirectives represents the reactgonative comment directives found above
+//a Go function or type declaration.
+//Code overrides the rejection code of an error variable or type.
+type GoDirectives struct {
+	Ignore bool
+	Sync   bool
+	Name   string
+	Thread string
+	Code   string
+}
@@ -0,0 +1,128 @@
+package types
+
+import (
+	"go/token"
+	"strings"
+)
+
+//Representations of an enum in JS
+const (
+	EnumString = "string"
+	EnumNumber = "number"
+)
+
+//IsEnumRepresentation identifies whether r is a known enum representation
+func IsEnumRepresentation(r string) bool {
+	return r == EnumString || r == EnumNumber
+}
+
+//GoEnum represents a named integer type along with the constants declared
+//of that type, the Go enum pattern.
+//Representation is how JS sees the values, EnumString unless configured.
+//Doc is the doc comment of the type.
+type GoEnum struct {
+	Name           string
+	Doc            string
+	Underlying     string
+	Values         []GoConstant
+	Representation string
+	Directives     GoDirectives
+	Pos            token.Position
+}
+
+//IsString identifies whether JS sees the enum as a union of strings
+func (e GoEnum) IsString() bool {
+	return e.Representation != EnumNumber
+}
+
+//JSValue is the string JS uses for the value c. The type name is trimmed
+//when every value of the enum is prefixed by it, so StatusActive is Active.
+func (e GoEnum) JSValue(c GoConstant) string {
+	for _, v := range e.Values {
+		if !strings.HasPrefix(v.Name, e.Name) || len(v.Name) == len(e.Name) {
+			return c.Name
+		}
+	}
+	return strings.TrimPrefix(c.Name, e.Name)
+}
+
+//Distinct returns the values of the enum, dropping any that repeat an
+//earlier value so each Go value maps to a single JS value
+func (e GoEnum) Distinct() []GoConstant {
+	seen := make(map[string]bool)
+	values := make([]GoConstant, 0, len(e.Values))
+	for _, v := range e.Values {
+		if !seen[v.Value] {
+			seen[v.Value] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+//isEnumUnderlying identifies the integer types an enum can be declared on
+func isEnumUnderlying(t string) bool {
+	switch t {
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		return true
+	}
+	return false
+}
+
+//GroupEnums moves the constants of named integer types declared in the
+//package out of Constants and into Enums, in the order the types are first used.
+//It should be called once every file of the package has been merged.
+func (g *GoType) GroupEnums() {
+	constants := make([]GoConstant, 0, len(g.Constants))
+	index := make(map[string]int)
+	for _, c := range g.Constants {
+		spec, ok := g.Types[c.T]
+		if !ok || spec.Kind != KindBasic || !isEnumUnderlying(spec.Underlying) {
+			constants = append(constants, c)
+			continue
+		}
+		i, ok := index[c.T]
+		if !ok {
+			i = len(g.Enums)
+			index[c.T] = i
+			g.Enums = append(g.Enums, GoEnum{
+				Name:       spec.Name,
+				Doc:        spec.Doc,
+				Underlying: spec.Underlying,
+				Directives: spec.Directives,
+				Pos:        spec.Pos,
+			})
+		}
+		g.Enums[i].Values = append(g.Enums[i].Values, c)
+	}
+	g.Constants = constants
+}
+
+//Enum returns the enum named t, if t is one. t may be qualified by a
+//linked package declaring the enum.
+func (g *GoType) Enum(t string) (*GoEnum, bool) {
+	owner, name := g.Resolve(t)
+	if owner == nil {
+		return nil, false
+	}
+	for i := range owner.Enums {
+		if owner.Enums[i].Name == name {
+			return &owner.Enums[i], true
+		}
+	}
+	return nil, false
+}
+
+//BridgeType is the Go type the Java bridge uses for t. Enums are passed as
+//strings unless configured as numbers, which pass as float64, the double
+//React Native passes JS numbers as, since it has no long.
+func (g *GoType) BridgeType(t string) string {
+	e, ok := g.Enum(t)
+	if !ok {
+		return t
+	}
+	if e.IsString() {
+		return "string"
+	}
+	return "float64"
+}
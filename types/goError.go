@@ -0,0 +1,55 @@
+package types
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+//GoError represents an error the functions of a package can return, which
+//promises are rejected with a stable Code for.
+//Message is the full message of a sentinel error, or when Prefix is set the
+//start of the message of a typed error.
+//Typed is set for errors declared as a type with a Code method.
+type GoError struct {
+	Name    string
+	Code    string
+	Message string
+	Prefix  bool
+	Typed   bool
+	Pos     token.Position
+}
+
+//ErrorCode derives a rejection code from the name of a sentinel error,
+//so ErrNotFound becomes E_NOT_FOUND
+func ErrorCode(name string) string {
+	runes := []rune(name)
+	if len(runes) > 3 && strings.EqualFold(string(runes[:3]), "err") && unicode.IsUpper(runes[3]) {
+		runes = runes[3:]
+	}
+	words := make([]string, 0)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		lowerBefore := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+		acronymEnd := i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+		if unicode.IsUpper(runes[i]) && (lowerBefore || acronymEnd) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+	return "E_" + strings.ToUpper(strings.Join(words, "_"))
+}
+
+//ErrorCodes returns the distinct codes of the errors in g, in order
+func (g *GoType) ErrorCodes() []string {
+	seen := make(map[string]bool)
+	codes := make([]string, 0, len(g.Errors))
+	for _, e := range g.Errors {
+		if !seen[e.Code] {
+			seen[e.Code] = true
+			codes = append(codes, e.Code)
+		}
+	}
+	return codes
+}
@@ -1,7 +1,57 @@
 package types
 
+import (
+	"go/token"
+	"strings"
+)
+
 //GoFunction represents a Go functions name and an array of parameters, if any.
+//Results holds every declared result, including a trailing error.
+//Receiver is the receiver type for methods, and blank for package functions.
+//Directives holds any reactgonative directives declared on the function,
+//and Doc its doc comment without them.
 type GoFunction struct {
-	Name   string
-	Params []GoParams
+	Name       string
+	Doc        string
+	Params     []GoParams
+	Results    []GoParams
+	Receiver   string
+	Directives GoDirectives
+	Pos        token.Position
+}
+
+//JSName is the name the function is exposed to JS with
+func (f *GoFunction) JSName() string {
+	if f.Directives.Name != "" {
+		return f.Directives.Name
+	}
+	return strings.ToLower(f.Name)
+}
+
+//Thread is the thread the bridge runs the Go call on. Synchronous functions
+//always run inline, on the JS thread calling them.
+func (f *GoFunction) Thread() string {
+	if f.Directives.Sync {
+		return ThreadInline
+	}
+	if f.Directives.Thread != "" {
+		return f.Directives.Thread
+	}
+	return ThreadInline
+}
+
+//Return is the first non error result of the function, the value the
+//bridge resolves with, or blank when there is none
+func (f *GoFunction) Return() GoParams {
+	for _, r := range f.Results {
+		if r.T != "error" {
+			return r
+		}
+	}
+	return GoParams{}
+}
+
+//ReturnsError identifies whether the last result of the function is an error
+func (f *GoFunction) ReturnsError() bool {
+	return len(f.Results) > 0 && f.Results[len(f.Results)-1].T == "error"
 }
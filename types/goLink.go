@@ -0,0 +1,72 @@
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+//Link resolves the imports of each package in pkgs against the others, so
+//types qualified by another package bound in the same run can be looked up.
+//It should be called again whenever pkgs is replaced, as the links point
+//into pkgs.
+func Link(pkgs []GoType) {
+	byPath := make(map[string]*GoType, len(pkgs))
+	for i := range pkgs {
+		byPath[pkgs[i].ImportPath] = &pkgs[i]
+	}
+	for i := range pkgs {
+		g := &pkgs[i]
+		g.Linked = make(map[string]*GoType)
+		for path, name := range g.Imports {
+			o, ok := byPath[path]
+			if !ok || o == g {
+				continue
+			}
+			if name == "" {
+				name = o.PackageName
+			}
+			if name == "_" || name == "." {
+				continue
+			}
+			g.Linked[name] = o
+		}
+	}
+}
+
+//Resolve returns the package declaring the named type t, and the name of
+//the type within it. Types qualified by a package not linked to g resolve
+//to a nil package.
+func (g *GoType) Resolve(t string) (*GoType, string) {
+	i := strings.Index(t, ".")
+	if i < 0 {
+		return g, t
+	}
+	return g.Linked[t[:i]], t[i+1:]
+}
+
+//LinkedEnums returns the qualifiers of the linked packages declaring enums
+//used by the functions in g, sorted
+func (g *GoType) LinkedEnums() []string {
+	seen := make(map[string]bool)
+	for i, f := range g.Functions {
+		if g.IsIgnored(i) {
+			continue
+		}
+		params := append([]GoParams{}, f.Params...)
+		for _, p := range append(params, f.Results...) {
+			i := strings.Index(p.T, ".")
+			if i < 0 {
+				continue
+			}
+			if _, ok := g.Enum(p.T); ok {
+				seen[p.T[:i]] = true
+			}
+		}
+	}
+	qualifiers := make([]string, 0, len(seen))
+	for q := range seen {
+		qualifiers = append(qualifiers, q)
+	}
+	sort.Strings(qualifiers)
+	return qualifiers
+}
@@ -1,16 +1,118 @@
 package types
 
-//GoType represents a single Go file
+import "strings"
+
+//GoType represents a single Go file.
+//JavaPkg is the -javapkg prefix gomobile binds the package under, if any.
+//Imports maps the import paths of the package to their explicit names,
+//blank when imported under the package name, and Linked maps the name of
+//each import bound in the same run to its package.
 type GoType struct {
 	PackageName string
+	ImportPath  string
+	JavaPkg     string
+	Imports     map[string]string
+	Linked      map[string]*GoType
 	Functions   []GoFunction
 	Returns     []GoParams
+	Types       map[string]GoTypeSpec
+	Constants   []GoConstant
+	Enums       []GoEnum
+	Errors      []GoError
 }
 
 //IsValid identifies whether the GoType holds valid data
 func (g *GoType) IsValid() bool {
-	if g.PackageName != "" && len(g.Functions) > 0 {
+	if g.PackageName != "" && (len(g.Functions) > 0 || len(g.Constants) > 0 || len(g.Enums) > 0) {
+		return true
+	}
+	return false
+}
+
+//Merge appends the functions and types declared in o, typically another file
+//of the same package, into g
+func (g *GoType) Merge(o GoType) {
+	g.Functions = append(g.Functions, o.Functions...)
+	g.Returns = append(g.Returns, o.Returns...)
+	g.Constants = append(g.Constants, o.Constants...)
+	g.Enums = append(g.Enums, o.Enums...)
+	g.Errors = append(g.Errors, o.Errors...)
+	for path, name := range o.Imports {
+		if g.Imports == nil {
+			g.Imports = make(map[string]string)
+		}
+		g.Imports[path] = name
+	}
+	for name, spec := range o.Types {
+		if g.Types == nil {
+			g.Types = make(map[string]GoTypeSpec)
+		}
+		g.Types[name] = spec
+	}
+}
+
+//IsIgnored identifies whether the function at index i should not be bridged,
+//either because it is marked ignore or because a parameter or return uses a
+//type marked ignore.
+func (g *GoType) IsIgnored(i int) bool {
+	f := g.Functions[i]
+	if f.Directives.Ignore {
 		return true
 	}
+	for _, p := range f.Params {
+		if g.isIgnoredType(p.T) {
+			return true
+		}
+	}
+	if i < len(g.Returns) && g.isIgnoredType(g.Returns[i].T) {
+		return true
+	}
+	return false
+}
+
+func (g *GoType) isIgnoredType(t string) bool {
+	owner, name := g.Resolve(strings.TrimLeft(t, "*[]"))
+	if owner == nil {
+		return false
+	}
+	if spec, ok := owner.Types[name]; ok {
+		return spec.Directives.Ignore
+	}
+	return false
+}
+
+//IsCallback identifies whether t names an interface declared in the package,
+//which the bridge implements to forward calls from Go to JS as events
+func (g *GoType) IsCallback(t string) bool {
+	spec, ok := g.Types[t]
+	return ok && spec.Kind == KindInterface
+}
+
+//Callbacks returns the interfaces used as parameters by the functions in g,
+//in the order they are first used
+func (g *GoType) Callbacks() []GoTypeSpec {
+	seen := make(map[string]bool)
+	callbacks := make([]GoTypeSpec, 0)
+	for i, f := range g.Functions {
+		if g.IsIgnored(i) {
+			continue
+		}
+		for _, p := range f.Params {
+			if g.IsCallback(p.T) && !seen[p.T] {
+				seen[p.T] = true
+				callbacks = append(callbacks, g.Types[p.T])
+			}
+		}
+	}
+	return callbacks
+}
+
+//UsesThread identifies whether any bridged function in g runs on thread
+func (g *GoType) UsesThread(thread string) bool {
+	for i, f := range g.Functions {
+		if !g.IsIgnored(i) && f.Thread() == thread {
+			return true
+		}
+	}
 	return false
 }
@@ -0,0 +1,26 @@
+package types
+
+import "go/token"
+
+//Kinds of named type a GoTypeSpec can declare
+const (
+	KindBasic     = "basic"
+	KindStruct    = "struct"
+	KindInterface = "interface"
+	KindOther     = "other"
+)
+
+//GoTypeSpec represents a named type declared in a Go package.
+//Underlying holds the underlying type name when Kind is KindBasic.
+//Methods and Embedded hold the exported methods and embedded interfaces
+//when Kind is KindInterface. Doc is the doc comment without directives.
+type GoTypeSpec struct {
+	Name       string
+	Doc        string
+	Kind       string
+	Underlying string
+	Methods    []GoFunction
+	Embedded   []string
+	Directives GoDirectives
+	Pos        token.Position
+}
@@ -11,3 +11,130 @@ func TestSetFileName(t *testing.T) {
 
 	})
 }
+
+func TestIsIgnored(t *testing.T) {
+	Convey("Given a go type with an ignored type", t, func() {
+		g := GoType{
+			PackageName: "pkg",
+			Functions: []GoFunction{
+				GoFunction{Name: "Visible"},
+				GoFunction{Name: "Hidden", Directives: GoDirectives{Ignore: true}},
+				GoFunction{Name: "UsesHidden", Params: []GoParams{GoParams{Name: "h", T: "*Secret"}}},
+				GoFunction{Name: "ReturnsHidden"},
+			},
+			Returns: []GoParams{
+				GoParams{},
+				GoParams{},
+				GoParams{},
+				GoParams{T: "[]Secret"},
+			},
+			Types: map[string]GoTypeSpec{
+				"Secret": GoTypeSpec{Name: "Secret", Kind: KindStruct, Directives: GoDirectives{Ignore: true}},
+			},
+		}
+		Convey("Then an undirected function is not ignored", func() {
+			So(g.IsIgnored(0), ShouldBeFalse)
+		})
+		Convey("And a function marked ignore is ignored", func() {
+			So(g.IsIgnored(1), ShouldBeTrue)
+		})
+		Convey("And a function with an ignored param type is ignored", func() {
+			So(g.IsIgnored(2), ShouldBeTrue)
+		})
+		Convey("And a function with an ignored return type is ignored", func() {
+			So(g.IsIgnored(3), ShouldBeTrue)
+		})
+	})
+}
+
+func TestGroupEnums(t *testing.T) {
+	Convey("Given constants of a named integer type", t, func() {
+		g := GoType{
+			Types: map[string]GoTypeSpec{
+				"Status": GoTypeSpec{Name: "Status", Kind: KindBasic, Underlying: "int"},
+				"Label":  GoTypeSpec{Name: "Label", Kind: KindBasic, Underlying: "string"},
+			},
+			Constants: []GoConstant{
+				GoConstant{Name: "StatusActive", T: "Status", Value: "0"},
+				GoConstant{Name: "Max", T: "int", Value: "3"},
+				GoConstant{Name: "StatusDone", T: "Status", Value: "1"},
+				GoConstant{Name: "StatusFinished", T: "Status", Value: "1"},
+				GoConstant{Name: "Title", T: "Label", Value: `"x"`},
+			},
+		}
+		Convey("When the enums are grouped", func() {
+			g.GroupEnums()
+			Convey("Then the values are moved into the enum", func() {
+				So(len(g.Enums), ShouldEqual, 1)
+				So(len(g.Enums[0].Values), ShouldEqual, 3)
+				So(len(g.Constants), ShouldEqual, 2)
+			})
+			Convey("And JS values are trimmed of the type name", func() {
+				So(g.Enums[0].JSValue(g.Enums[0].Values[0]), ShouldEqual, "Active")
+			})
+			Convey("And repeated values are dropped from the distinct values", func() {
+				So(len(g.Enums[0].Distinct()), ShouldEqual, 2)
+			})
+			Convey("And enums are bridged as strings by default", func() {
+				So(g.BridgeType("Status"), ShouldEqual, "string")
+				g.Enums[0].Representation = EnumNumber
+				So(g.BridgeType("Status"), ShouldEqual, "float64")
+			})
+		})
+	})
+}
+
+func TestErrorCode(t *testing.T) {
+	Convey("Given the names of sentinel errors", t, func() {
+		Convey("Then codes are upper snake case without the Err prefix", func() {
+			So(ErrorCode("ErrNotFound"), ShouldEqual, "E_NOT_FOUND")
+			So(ErrorCode("ErrHTTPTimeout"), ShouldEqual, "E_HTTP_TIMEOUT")
+			So(ErrorCode("EOF"), ShouldEqual, "E_EOF")
+			So(ErrorCode("Errata"), ShouldEqual, "E_ERRATA")
+		})
+	})
+}
+
+func TestLink(t *testing.T) {
+	Convey("Given a package using an enum from another bound package", t, func() {
+		pkgs := []GoType{
+			GoType{
+				PackageName: "jobs",
+				ImportPath:  "example.com/jobs",
+				Imports:     map[string]string{"example.com/shared/status": "st", "fmt": ""},
+				Functions: []GoFunction{
+					GoFunction{Name: "Current", Results: []GoParams{GoParams{T: "st.Status"}}},
+					GoFunction{Name: "Hidden", Params: []GoParams{GoParams{T: "*st.Secret"}}},
+				},
+				Returns: []GoParams{GoParams{T: "st.Status"}, GoParams{}},
+			},
+			GoType{
+				PackageName: "status",
+				ImportPath:  "example.com/shared/status",
+				Types:       map[string]GoTypeSpec{"Secret": GoTypeSpec{Name: "Secret", Directives: GoDirectives{Ignore: true}}},
+				Enums:       []GoEnum{GoEnum{Name: "Status", Underlying: "int"}},
+			},
+		}
+		Convey("When the packages are linked", func() {
+			Link(pkgs)
+			Convey("Then the import is linked under its explicit name", func() {
+				So(pkgs[0].Linked["st"], ShouldEqual, &pkgs[1])
+				So(len(pkgs[0].Linked), ShouldEqual, 1)
+			})
+			Convey("And the qualified enum resolves to the other package", func() {
+				e, ok := pkgs[0].Enum("st.Status")
+				So(ok, ShouldBeTrue)
+				So(e, ShouldEqual, &pkgs[1].Enums[0])
+				So(pkgs[0].LinkedEnums(), ShouldResemble, []string{"st"})
+			})
+			Convey("And types ignored in the other package are ignored", func() {
+				So(pkgs[0].IsIgnored(1), ShouldBeTrue)
+			})
+			Convey("And packages not bound do not resolve", func() {
+				owner, name := pkgs[0].Resolve("fmt.Stringer")
+				So(owner, ShouldBeNil)
+				So(name, ShouldEqual, "Stringer")
+			})
+		})
+	})
+}
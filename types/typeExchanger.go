@@ -2,6 +2,20 @@ package types
 
 import "strings"
 
+var basicTypes = map[string]bool{
+	"bool": true, "string": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"byte": true, "rune": true,
+	"float32": true, "float64": true,
+	"complex64": true, "complex128": true,
+}
+
+//IsBasicType identifies whether t names one of Go's predeclared basic types
+func IsBasicType(t string) bool {
+	return basicTypes[t]
+}
+
 //GoToJava converts the goIn Go type, to the Java representation.
 //BUG - Unfinished
 func GoToJava(goIn string) string {
@@ -9,12 +23,38 @@ func GoToJava(goIn string) string {
 	switch x {
 	case "string":
 		return "String"
-	case "int":
+	case "int", "int64":
 		return "long"
+	case "bool":
+		return "boolean"
+	case "int8":
+		return "byte"
+	case "int16":
+		return "short"
+	case "int32", "rune":
+		return "int"
+	case "float32":
+		return "float"
+	case "float64":
+		return "double"
 	}
 	return goIn
 }
 
+//GoToTS converts the goIn Go type, to the TypeScript representation.
+//Types without a representation map to any.
+func GoToTS(goIn string) string {
+	switch goIn {
+	case "string":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64", "rune", "float32", "float64":
+		return "number"
+	}
+	return "any"
+}
+
 //JavaToGo converts the javaIn Java type, to the Go representation
 //BUG - Unfinished
 func JavaToGo(javaIn string) string {
@@ -0,0 +1,368 @@
+package validator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/steve-winter/reactgonative/diagnostics"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+//Issue represents a single exported symbol that will not be bridged.
+//Failure is set when gomobile bind itself would refuse or reshape the symbol,
+//otherwise the symbol is valid for gomobile but skipped by the bridge.
+//Ignored is set when a directive asked for the symbol to be skipped.
+type Issue struct {
+	Pos     token.Position
+	Symbol  string
+	Message string
+	Failure bool
+	Ignored bool
+}
+
+func (i Issue) String() string {
+	kind := "skipped"
+	if i.Failure {
+		kind = "not bindable"
+	}
+	return fmt.Sprintf("%s: %s %s: %s", i.Pos, kind, i.Symbol, i.Message)
+}
+
+//Diagnostic converts the issue into a warning, or a note when the symbol was
+//deliberately ignored
+func (i Issue) Diagnostic() diagnostics.Diagnostic {
+	d := diagnostics.Diagnostic{
+		Pos:      i.Pos,
+		Severity: diagnostics.Warning,
+		Code:     diagnostics.CodeNotBridged,
+		Message:  i.Symbol + ": " + i.Message,
+	}
+	if i.Failure {
+		d.Code = diagnostics.CodeNotBindable
+	}
+	if i.Ignored {
+		d.Severity = diagnostics.Note
+		d.Code = diagnostics.CodeIgnored
+	}
+	return d
+}
+
+//Validate checks every exported function and type in g against gomobile's
+//bind rules, and against the types the bridge can currently marshal.
+//The returned GoType only holds the functions and constants that can be
+//bridged, along with an Issue for each symbol removed.
+func Validate(g types.GoType) (types.GoType, []Issue) {
+	issues := make([]Issue, 0)
+	valid := g
+	valid.Functions = make([]types.GoFunction, 0, len(g.Functions))
+	valid.Returns = make([]types.GoParams, 0, len(g.Returns))
+	names := make(map[string]string)
+	for i, f := range g.Functions {
+		if g.IsIgnored(i) {
+			issues = append(issues, Issue{Pos: f.Pos, Symbol: f.Name, Message: "ignored by reactgonative directive", Ignored: true})
+			continue
+		}
+		if issue, ok := validateFunction(&g, f); !ok {
+			issues = append(issues, issue)
+			continue
+		}
+		if other, ok := names[f.JSName()]; ok {
+			issues = append(issues, Issue{Pos: f.Pos, Symbol: f.Name,
+				Message: fmt.Sprintf("JS name %s is already used by %s", f.JSName(), other)})
+			continue
+		}
+		names[f.JSName()] = f.Name
+		valid.Functions = append(valid.Functions, f)
+		valid.Returns = append(valid.Returns, g.Returns[i])
+	}
+	valid.Constants, issues = validateConstants(g.Constants, issues)
+	valid.Enums, issues = validateEnums(g.Enums, issues)
+	return valid, append(issues, validateTypes(&g)...)
+}
+
+//validateEnums removes ignored enums, and ignored values from the rest
+func validateEnums(enums []types.GoEnum, issues []Issue) ([]types.GoEnum, []Issue) {
+	valid := make([]types.GoEnum, 0, len(enums))
+	for _, e := range enums {
+		if e.Directives.Ignore {
+			issues = append(issues, Issue{Pos: e.Pos, Symbol: e.Name, Message: "ignored by reactgonative directive", Ignored: true})
+			continue
+		}
+		values := make([]types.GoConstant, 0, len(e.Values))
+		for _, v := range e.Values {
+			if v.Directives.Ignore {
+				issues = append(issues, Issue{Pos: v.Pos, Symbol: v.Name, Message: "ignored by reactgonative directive", Ignored: true})
+				continue
+			}
+			values = append(values, v)
+		}
+		e.Values = values
+		valid = append(valid, e)
+	}
+	return valid, issues
+}
+
+//validateConstants filters constants down to those gomobile binds as static
+//fields, which excludes constants of named types
+func validateConstants(constants []types.GoConstant, issues []Issue) ([]types.GoConstant, []Issue) {
+	valid := make([]types.GoConstant, 0, len(constants))
+	for _, c := range constants {
+		issue := Issue{Pos: c.Pos, Symbol: c.Name}
+		switch {
+		case c.Directives.Ignore:
+			issue.Message = "ignored by reactgonative directive"
+			issue.Ignored = true
+		case !types.IsBasicType(c.T):
+			issue.Message = fmt.Sprintf("constants of type %s are not bound by gomobile", c.T)
+			issue.Failure = true
+		default:
+			issue.Message = basicMessage(c.T)
+			issue.Failure = issue.Message != ""
+		}
+		if issue.Message != "" {
+			issues = append(issues, issue)
+			continue
+		}
+		valid = append(valid, c)
+	}
+	return valid, issues
+}
+
+func validateTypes(g *types.GoType) []Issue {
+	names := make([]string, 0, len(g.Types))
+	for name := range g.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	issues := make([]Issue, 0)
+	for _, name := range names {
+		spec := g.Types[name]
+		if !ast.IsExported(name) {
+			continue
+		}
+		msg := ""
+		switch spec.Kind {
+		case types.KindOther:
+			msg = "only struct, interface and basic named types can be bound"
+		case types.KindBasic:
+			msg = basicMessage(spec.Underlying)
+		}
+		if msg != "" {
+			issues = append(issues, Issue{Pos: spec.Pos, Symbol: name, Message: msg, Failure: true})
+		}
+	}
+	return issues
+}
+
+func validateFunction(g *types.GoType, f types.GoFunction) (Issue, bool) {
+	issue := Issue{Pos: f.Pos, Symbol: f.Name}
+	if f.Receiver != "" {
+		issue.Symbol = f.Receiver + "." + f.Name
+		issue.Message = "methods are not bridged"
+		return issue, false
+	}
+	if msg := nameMessage(f.JSName()); msg != "" {
+		issue.Message = msg
+		return issue, false
+	}
+	results := f.Results
+	if len(results) > 2 || (len(results) == 2 && results[1].T != "error") {
+		issue.Message = "gomobile only binds a single result, optionally followed by an error"
+		issue.Failure = true
+		return issue, false
+	}
+	bridged := true
+	reason := ""
+	for _, p := range f.Params {
+		bindable, bridgeable, msg := classify(g, p.T)
+		if !bindable {
+			issue.Message = fmt.Sprintf("parameter %s: %s", p.Name, msg)
+			issue.Failure = true
+			return issue, false
+		}
+		if p.T == "error" {
+			bridgeable, msg = false, "error parameters are not supported by the bridge"
+		}
+		if g.IsCallback(p.T) {
+			msg = callbackMessage(g, g.Types[p.T])
+			bridgeable = msg == ""
+		}
+		if !bridgeable && bridged {
+			bridged, reason = false, fmt.Sprintf("parameter %s: %s", p.Name, msg)
+		}
+	}
+	for _, r := range results {
+		bindable, bridgeable, msg := classify(g, r.T)
+		if !bindable {
+			issue.Message = "result: " + msg
+			issue.Failure = true
+			return issue, false
+		}
+		if !bridgeable && bridged {
+			bridged, reason = false, "result: "+msg
+		}
+	}
+	if !bridged {
+		issue.Message = reason
+		return issue, false
+	}
+	return issue, true
+}
+
+//reservedNames are the Java keywords and JS reserved words a Go function
+//name can lower case to
+var reservedNames = map[string]bool{
+	"abstract": true, "arguments": true, "assert": true, "await": true,
+	"boolean": true, "break": true, "byte": true, "case": true, "catch": true,
+	"char": true, "class": true, "const": true, "continue": true, "debugger": true,
+	"default": true, "delete": true, "do": true, "double": true, "else": true,
+	"enum": true, "eval": true, "export": true, "extends": true, "false": true,
+	"final": true, "finally": true, "float": true, "for": true, "function": true,
+	"goto": true, "if": true, "implements": true, "import": true, "in": true,
+	"instanceof": true, "int": true, "interface": true, "let": true, "long": true,
+	"native": true, "new": true, "null": true, "package": true, "private": true,
+	"protected": true, "public": true, "return": true, "short": true, "static": true,
+	"strictfp": true, "super": true, "switch": true, "synchronized": true,
+	"this": true, "throw": true, "throws": true, "transient": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "volatile": true,
+	"while": true, "with": true, "yield": true,
+}
+
+//moduleMethods are the methods every generated module declares itself
+var moduleMethods = map[string]bool{
+	"addListener": true, "getConstants": true, "getName": true,
+	"onCatalystInstanceDestroy": true, "rejectGoError": true, "removeListeners": true,
+}
+
+//nameMessage describes why name cannot be used for a bridged method, or
+//returns blank if it can
+func nameMessage(name string) string {
+	if reservedNames[name] {
+		return fmt.Sprintf("JS name %s is reserved in Java or JS, rename it with reactgonative:name", name)
+	}
+	if moduleMethods[name] {
+		return fmt.Sprintf("JS name %s is used by the generated module, rename it with reactgonative:name", name)
+	}
+	for i, r := range name {
+		if !(r == '_' || r == '$' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return fmt.Sprintf("JS name %q is not a valid identifier", name)
+		}
+	}
+	return ""
+}
+
+//callbackMessage describes why the interface spec cannot be implemented by
+//the bridge as a callback, or returns blank if it can
+func callbackMessage(g *types.GoType, spec types.GoTypeSpec) string {
+	if len(spec.Embedded) > 0 {
+		return fmt.Sprintf("callback %s embeds interfaces, which are not supported by the bridge", spec.Name)
+	}
+	for _, m := range spec.Methods {
+		if len(m.Results) > 0 {
+			return fmt.Sprintf("callback method %s.%s must not return results", spec.Name, m.Name)
+		}
+		for _, p := range m.Params {
+			if _, ok := g.Enum(p.T); ok {
+				return fmt.Sprintf("callback method %s.%s parameter %s: enums are not supported in callbacks", spec.Name, m.Name, p.Name)
+			}
+			bindable, bridgeable, msg := classify(g, p.T)
+			if !bindable || !bridgeable || p.T == "error" {
+				if msg == "" {
+					msg = "error parameters are not supported by the bridge"
+				}
+				return fmt.Sprintf("callback method %s.%s parameter %s: %s", spec.Name, m.Name, p.Name, msg)
+			}
+		}
+	}
+	return ""
+}
+
+//classify reports whether gomobile can bind the Go type t, whether the bridge
+//can marshal it, and a message describing the first that cannot
+func classify(g *types.GoType, t string) (bindable bool, bridgeable bool, msg string) {
+	switch {
+	case t == "error":
+		return true, true, ""
+	case types.IsBasicType(t):
+		if m := basicMessage(t); m != "" {
+			return false, false, m
+		}
+		return true, true, ""
+	case t == "[]byte":
+		return true, false, "[]byte is not yet supported by the bridge"
+	case strings.HasPrefix(t, "..."):
+		return false, false, "variadic parameters are not supported by gomobile"
+	case strings.HasPrefix(t, "["), strings.HasPrefix(t, "map["),
+		strings.HasPrefix(t, "chan "), strings.HasPrefix(t, "<-chan"),
+		strings.HasPrefix(t, "func("), strings.HasPrefix(t, "interface{"),
+		strings.HasPrefix(t, "struct{"):
+		return false, false, fmt.Sprintf("type %s is not supported by gomobile", t)
+	case strings.Contains(t, "."):
+		return classifyLinked(g, t)
+	}
+	name := strings.TrimPrefix(t, "*")
+	if !ast.IsExported(name) {
+		return false, false, fmt.Sprintf("unexported type %s is not supported by gomobile", t)
+	}
+	spec, ok := g.Types[name]
+	if !ok {
+		return false, false, fmt.Sprintf("type %s is not declared in package %s", t, g.PackageName)
+	}
+	pointer := name != t
+	switch spec.Kind {
+	case types.KindStruct:
+		if !pointer {
+			return false, false, fmt.Sprintf("struct %s must be passed by pointer", name)
+		}
+		return true, false, fmt.Sprintf("struct %s is not yet supported by the bridge", name)
+	case types.KindInterface:
+		if pointer {
+			return false, false, fmt.Sprintf("pointer to interface %s is not supported by gomobile", name)
+		}
+		return true, false, fmt.Sprintf("interface %s is only supported by the bridge as a callback parameter", name)
+	case types.KindBasic:
+		//gomobile only binds named interfaces and pointers to named structs,
+		//so enums must be passed as their underlying type
+		if m := basicMessage(spec.Underlying); m != "" || pointer {
+			return false, false, fmt.Sprintf("type %s is not supported by gomobile", t)
+		}
+		if _, ok := g.Enum(name); ok {
+			return false, false, fmt.Sprintf("enum %s is not supported by gomobile, use its underlying type %s", name, spec.Underlying)
+		}
+		return false, false, fmt.Sprintf("named type %s is not supported by gomobile, use its underlying type %s", name, spec.Underlying)
+	}
+	return false, false, fmt.Sprintf("type %s is not supported by gomobile", t)
+}
+
+//classifyLinked classifies the type t qualified by another package, within
+//the package declaring it when that is bound in the same run
+func classifyLinked(g *types.GoType, t string) (bindable bool, bridgeable bool, msg string) {
+	name := strings.TrimPrefix(t, "*")
+	owner, local := g.Resolve(name)
+	if owner == nil {
+		return true, false, fmt.Sprintf("type %s is from a package not bound in this run", t)
+	}
+	if spec, ok := owner.Types[local]; ok && spec.Kind == types.KindInterface && name == t {
+		return true, false, fmt.Sprintf("interface %s from another package is not supported by the bridge", t)
+	}
+	if name != t {
+		local = "*" + local
+	}
+	return classify(owner, local)
+}
+
+//basicMessage describes why gomobile refuses the basic type t, or returns
+//blank for the signed integer, float, string and bool types it supports
+func basicMessage(t string) string {
+	switch t {
+	case "bool", "string", "int", "int8", "int16", "int32", "int64", "rune", "float32", "float64":
+		return ""
+	case "complex64", "complex128":
+		return "complex types are not supported by gomobile"
+	}
+	return "unsigned integer types are not supported by gomobile"
+}
@@ -0,0 +1,269 @@
+package validator
+
+import (
+	"go/token"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/steve-winter/reactgonative/diagnostics"
+	"github.com/steve-winter/reactgonative/types"
+)
+
+func testType() types.GoType {
+	return types.GoType{
+		PackageName: "pkg",
+		Functions: []types.GoFunction{
+			types.GoFunction{Name: "Greet", Params: []types.GoParams{types.GoParams{Name: "name", T: "string"}},
+				Results: []types.GoParams{types.GoParams{T: "string"}, types.GoParams{T: "error"}}},
+			types.GoFunction{Name: "Pair", Results: []types.GoParams{types.GoParams{T: "int"}, types.GoParams{T: "string"}},
+				Pos: token.Position{Filename: "pkg.go", Line: 7, Column: 6}},
+			types.GoFunction{Name: "Count", Params: []types.GoParams{types.GoParams{Name: "n", T: "uint"}}},
+			types.GoFunction{Name: "Hidden", Params: []types.GoParams{types.GoParams{Name: "c", T: "*config"}}},
+			types.GoFunction{Name: "Load", Params: []types.GoParams{types.GoParams{Name: "c", T: "*Config"}}},
+			types.GoFunction{Name: "ByValue", Params: []types.GoParams{types.GoParams{Name: "c", T: "Config"}}},
+			types.GoFunction{Name: "Close", Receiver: "*Config"},
+			types.GoFunction{Name: "Lookup", Params: []types.GoParams{types.GoParams{Name: "m", T: "map[string]int"}}},
+		},
+		Returns: make([]types.GoParams, 8),
+		Types: map[string]types.GoTypeSpec{
+			"Config":  types.GoTypeSpec{Name: "Config", Kind: types.KindStruct},
+			"config":  types.GoTypeSpec{Name: "config", Kind: types.KindStruct},
+			"Handler": types.GoTypeSpec{Name: "Handler", Kind: types.KindOther},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	Convey("Given a go type with bindable and unbindable functions", t, func() {
+		g := testType()
+		Convey("When it is validated", func() {
+			valid, issues := Validate(g)
+			Convey("Then only the bridgeable function remains", func() {
+				So(len(valid.Functions), ShouldEqual, 1)
+				So(valid.Functions[0].Name, ShouldEqual, "Greet")
+				So(len(valid.Returns), ShouldEqual, 1)
+			})
+			Convey("And every other symbol has an issue", func() {
+				So(len(issues), ShouldEqual, 8)
+			})
+			Convey("And multiple non error results fail with a position", func() {
+				So(issues[0].Symbol, ShouldEqual, "Pair")
+				So(issues[0].Failure, ShouldBeTrue)
+				So(issues[0].String(), ShouldStartWith, "pkg.go:7:6: not bindable Pair")
+			})
+			Convey("And failures convert to not bindable warnings", func() {
+				d := issues[0].Diagnostic()
+				So(d.Severity, ShouldEqual, diagnostics.Warning)
+				So(d.Code, ShouldEqual, diagnostics.CodeNotBindable)
+				So(d.Pos.Line, ShouldEqual, 7)
+			})
+			Convey("And unsigned params fail", func() {
+				So(issues[1].Failure, ShouldBeTrue)
+				So(issues[1].Message, ShouldContainSubstring, "unsigned")
+			})
+			Convey("And unexported types fail", func() {
+				So(issues[2].Failure, ShouldBeTrue)
+				So(issues[2].Message, ShouldContainSubstring, "unexported type *config")
+			})
+			Convey("And struct pointers are bindable but skipped", func() {
+				So(issues[3].Failure, ShouldBeFalse)
+			})
+			Convey("And structs by value fail", func() {
+				So(issues[4].Failure, ShouldBeTrue)
+			})
+			Convey("And methods are skipped", func() {
+				So(issues[5].Symbol, ShouldEqual, "*Config.Close")
+				So(issues[5].Failure, ShouldBeFalse)
+			})
+			Convey("And maps fail", func() {
+				So(issues[6].Failure, ShouldBeTrue)
+			})
+			Convey("And unsupported named types fail", func() {
+				So(issues[7].Symbol, ShouldEqual, "Handler")
+				So(issues[7].Failure, ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given a function marked ignore", t, func() {
+		g := types.GoType{
+			PackageName: "pkg",
+			Functions:   []types.GoFunction{types.GoFunction{Name: "Skip", Directives: types.GoDirectives{Ignore: true}}},
+			Returns:     []types.GoParams{types.GoParams{}},
+		}
+		Convey("When it is validated", func() {
+			valid, issues := Validate(g)
+			Convey("Then it is removed and reported as a skip", func() {
+				So(len(valid.Functions), ShouldEqual, 0)
+				So(issues[0].Failure, ShouldBeFalse)
+			})
+			Convey("And its diagnostic is a note", func() {
+				So(issues[0].Diagnostic().Severity, ShouldEqual, diagnostics.Note)
+				So(issues[0].Diagnostic().Code, ShouldEqual, diagnostics.CodeIgnored)
+			})
+		})
+	})
+	Convey("Given constants of several types", t, func() {
+		g := types.GoType{
+			PackageName: "pkg",
+			Constants: []types.GoConstant{
+				types.GoConstant{Name: "Max", T: "int", Value: "10"},
+				types.GoConstant{Name: "Mask", T: "uint8", Value: "255"},
+				types.GoConstant{Name: "Active", T: "Status", Value: "1"},
+				types.GoConstant{Name: "Secret", T: "string", Value: `"x"`, Directives: types.GoDirectives{Ignore: true}},
+			},
+		}
+		Convey("When they are validated", func() {
+			valid, issues := Validate(g)
+			Convey("Then only bindable constants are kept", func() {
+				So(len(valid.Constants), ShouldEqual, 1)
+				So(valid.Constants[0].Name, ShouldEqual, "Max")
+			})
+			Convey("And unsigned and named types are not bindable", func() {
+				So(issues[0].Symbol, ShouldEqual, "Mask")
+				So(issues[0].Failure, ShouldBeTrue)
+				So(issues[1].Symbol, ShouldEqual, "Active")
+				So(issues[1].Failure, ShouldBeTrue)
+			})
+			Convey("And ignored constants are skipped", func() {
+				So(issues[2].Ignored, ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given a function taking an enum", t, func() {
+		g := types.GoType{
+			PackageName: "pkg",
+			Functions: []types.GoFunction{types.GoFunction{Name: "SetStatus",
+				Params: []types.GoParams{types.GoParams{Name: "s", T: "Status"}}}},
+			Returns: make([]types.GoParams, 1),
+			Types: map[string]types.GoTypeSpec{
+				"Status": types.GoTypeSpec{Name: "Status", Kind: types.KindBasic, Underlying: "int"},
+			},
+			Enums: []types.GoEnum{types.GoEnum{Name: "Status", Underlying: "int", Values: []types.GoConstant{
+				types.GoConstant{Name: "StatusActive", T: "Status", Value: "0"},
+				types.GoConstant{Name: "StatusLegacy", T: "Status", Value: "1", Directives: types.GoDirectives{Ignore: true}},
+			}}},
+		}
+		Convey("When it is validated", func() {
+			valid, issues := Validate(g)
+			Convey("Then the function is not bindable, as gomobile only binds the underlying type", func() {
+				So(len(valid.Functions), ShouldEqual, 0)
+				So(issues[0].Symbol, ShouldEqual, "SetStatus")
+				So(issues[0].Failure, ShouldBeTrue)
+				So(issues[0].Message, ShouldEqual, "parameter s: enum Status is not supported by gomobile, use its underlying type int")
+			})
+			Convey("And ignored values are dropped from the enum", func() {
+				So(len(valid.Enums[0].Values), ShouldEqual, 1)
+				So(issues[1].Symbol, ShouldEqual, "StatusLegacy")
+				So(issues[1].Ignored, ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given functions using types from other packages", t, func() {
+		pkgs := []types.GoType{
+			types.GoType{
+				PackageName: "jobs",
+				Imports:     map[string]string{"example.com/status": "", "example.com/other": ""},
+				Functions: []types.GoFunction{
+					types.GoFunction{Name: "SetStatus", Params: []types.GoParams{types.GoParams{Name: "s", T: "status.Status"}}},
+					types.GoFunction{Name: "Watch", Params: []types.GoParams{types.GoParams{Name: "w", T: "status.Watcher"}}},
+					types.GoFunction{Name: "Other", Params: []types.GoParams{types.GoParams{Name: "o", T: "other.Thing"}}},
+					types.GoFunction{Name: "Value", Params: []types.GoParams{types.GoParams{Name: "s", T: "status.Info"}}},
+				},
+				Returns: make([]types.GoParams, 4),
+			},
+			types.GoType{
+				PackageName: "status",
+				ImportPath:  "example.com/status",
+				Types: map[string]types.GoTypeSpec{
+					"Status":  types.GoTypeSpec{Name: "Status", Kind: types.KindBasic, Underlying: "int"},
+					"Watcher": types.GoTypeSpec{Name: "Watcher", Kind: types.KindInterface},
+					"Info":    types.GoTypeSpec{Name: "Info", Kind: types.KindStruct},
+				},
+				Enums: []types.GoEnum{types.GoEnum{Name: "Status", Underlying: "int"}},
+			},
+		}
+		types.Link(pkgs)
+		Convey("When the first is validated", func() {
+			valid, issues := Validate(pkgs[0])
+			Convey("Then no function is bridged", func() {
+				So(len(valid.Functions), ShouldEqual, 0)
+			})
+			Convey("And enums from a bound package are not bindable", func() {
+				So(issues[0].Message, ShouldContainSubstring, "enum Status is not supported by gomobile")
+				So(issues[0].Failure, ShouldBeTrue)
+			})
+			Convey("And interfaces from another package are not bridged", func() {
+				So(issues[1].Message, ShouldContainSubstring, "interface status.Watcher from another package")
+				So(issues[1].Failure, ShouldBeFalse)
+			})
+			Convey("And types from packages not bound are not bridged", func() {
+				So(issues[2].Message, ShouldContainSubstring, "not bound in this run")
+			})
+			Convey("And other types are checked in their own package", func() {
+				So(issues[3].Message, ShouldContainSubstring, "struct Info must be passed by pointer")
+				So(issues[3].Failure, ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given functions whose JS names cannot be used", t, func() {
+		g := types.GoType{
+			PackageName: "hello",
+			Functions: []types.GoFunction{
+				types.GoFunction{Name: "Greet"},
+				types.GoFunction{Name: "GREET"},
+				types.GoFunction{Name: "Class"},
+				types.GoFunction{Name: "Delete", Directives: types.GoDirectives{Name: "remove"}},
+				types.GoFunction{Name: "Dashed", Directives: types.GoDirectives{Name: "dashed-name"}},
+			},
+			Returns: make([]types.GoParams, 5),
+		}
+		Convey("When it is validated", func() {
+			valid, issues := Validate(g)
+			Convey("Then only the first of a JS name and renamed functions are bridged", func() {
+				So(len(valid.Functions), ShouldEqual, 2)
+				So(valid.Functions[0].Name, ShouldEqual, "Greet")
+				So(valid.Functions[1].Name, ShouldEqual, "Delete")
+			})
+			Convey("And the others are skipped with the reason", func() {
+				So(len(issues), ShouldEqual, 3)
+				So(issues[0].Message, ShouldEqual, "JS name greet is already used by Greet")
+				So(issues[1].Message, ShouldContainSubstring, "reserved in Java or JS")
+				So(issues[2].Message, ShouldContainSubstring, "not a valid identifier")
+				So(issues[2].Failure, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestValidateCallbacks(t *testing.T) {
+	Convey("Given functions taking interfaces", t, func() {
+		g := types.GoType{
+			PackageName: "pkg",
+			Functions: []types.GoFunction{
+				types.GoFunction{Name: "Watch", Params: []types.GoParams{types.GoParams{Name: "c", T: "Counter"}}},
+				types.GoFunction{Name: "Ask", Params: []types.GoParams{types.GoParams{Name: "q", T: "Question"}}},
+			},
+			Returns: make([]types.GoParams, 2),
+			Types: map[string]types.GoTypeSpec{
+				"Counter": types.GoTypeSpec{Name: "Counter", Kind: types.KindInterface, Methods: []types.GoFunction{
+					types.GoFunction{Name: "OnProgress", Params: []types.GoParams{types.GoParams{Name: "n", T: "int"}}},
+				}},
+				"Question": types.GoTypeSpec{Name: "Question", Kind: types.KindInterface, Methods: []types.GoFunction{
+					types.GoFunction{Name: "Answer", Results: []types.GoParams{types.GoParams{T: "string"}}},
+				}},
+			},
+		}
+		Convey("When they are validated", func() {
+			valid, issues := Validate(g)
+			Convey("Then the callback interface is bridged", func() {
+				So(len(valid.Functions), ShouldEqual, 1)
+				So(valid.Functions[0].Name, ShouldEqual, "Watch")
+			})
+			Convey("And the interface returning results is skipped", func() {
+				So(issues[0].Symbol, ShouldEqual, "Ask")
+				So(issues[0].Failure, ShouldBeFalse)
+				So(issues[0].Message, ShouldContainSubstring, "must not return results")
+			})
+		})
+	})
+}
@@ -0,0 +1,110 @@
+package watcher
+
+import (
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+)
+
+//File is the state of a Go source file, enough to tell it was edited
+type File struct {
+	Size    int64
+	ModTime time.Time
+}
+
+//Snapshot holds the Go files of each watched package, keyed by the package
+//import path and then by file name
+type Snapshot map[string]map[string]File
+
+//Take returns the snapshot of the non-test Go files in the directory of
+//each package, as named by folder
+func Take(paths []string, folder func(path string) string) (Snapshot, error) {
+	s := make(Snapshot, len(paths))
+	for _, path := range paths {
+		infos, err := ioutil.ReadDir(folder(path))
+		if err != nil {
+			return nil, err
+		}
+		files := make(map[string]File)
+		for _, info := range infos {
+			name := info.Name()
+			if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			files[name] = File{Size: info.Size(), ModTime: info.ModTime()}
+		}
+		s[path] = files
+	}
+	return s, nil
+}
+
+//Changed returns the import paths of the packages added, removed or with a
+//file added, removed or edited from old to next, in order
+func Changed(old Snapshot, next Snapshot) []string {
+	changed := make([]string, 0)
+	for path, files := range next {
+		if !sameFiles(old[path], files) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range old {
+		if _, ok := next[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func sameFiles(old map[string]File, next map[string]File) bool {
+	if old == nil || len(old) != len(next) {
+		return false
+	}
+	for name, f := range next {
+		o, ok := old[name]
+		if !ok || o.Size != f.Size || !o.ModTime.Equal(f.ModTime) {
+			return false
+		}
+	}
+	return true
+}
+
+//Watcher polls for changes to Go packages, which works on any file system
+//without notification support
+type Watcher struct {
+	//Interval is the time between scans. Changes are reported once a scan
+	//finds nothing changed since the one before, so a save touching several
+	//files is reported once.
+	Interval time.Duration
+	//Scan returns the current snapshot. A failed scan, such as one made
+	//while a file is being replaced, is retried on the next tick.
+	Scan func() (Snapshot, error)
+}
+
+//Wait polls until the packages change from last and then settle, and
+//returns the settled snapshot and the packages changed. It returns false
+//once stop is closed.
+func (w *Watcher) Wait(last Snapshot, stop <-chan struct{}) (Snapshot, []string, bool) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	var pending Snapshot
+	for {
+		select {
+		case <-stop:
+			return nil, nil, false
+		case <-ticker.C:
+		}
+		s, err := w.Scan()
+		if err != nil {
+			continue
+		}
+		if pending != nil && len(Changed(pending, s)) == 0 {
+			return s, Changed(last, s), true
+		}
+		pending = nil
+		if len(Changed(last, s)) > 0 {
+			pending = s
+		}
+	}
+}
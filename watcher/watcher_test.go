@@ -0,0 +1,110 @@
+package watcher
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTake(t *testing.T) {
+	Convey("Given a package directory", t, func() {
+		root := t.TempDir()
+		dir := filepath.Join(root, "hello")
+		So(os.MkdirAll(filepath.Join(dir, "sub.go"), 0777), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(dir, "hello.go"), []byte("package hello\n"), 0644), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(dir, "hello_test.go"), []byte("package hello\n"), 0644), ShouldBeNil)
+		So(ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("hello\n"), 0644), ShouldBeNil)
+		folder := func(path string) string {
+			return filepath.Join(root, path)
+		}
+		s, err := Take([]string{"hello"}, folder)
+		So(err, ShouldBeNil)
+		Convey("Then only its Go source files are recorded", func() {
+			So(len(s["hello"]), ShouldEqual, 1)
+			So(s["hello"]["hello.go"].Size, ShouldEqual, 14)
+		})
+		Convey("When a source file is edited", func() {
+			So(ioutil.WriteFile(filepath.Join(dir, "hello.go"), []byte("package hello\n\nfunc Hi() {}\n"), 0644), ShouldBeNil)
+			next, err := Take([]string{"hello"}, folder)
+			So(err, ShouldBeNil)
+			Convey("Then the package has changed", func() {
+				So(Changed(s, next), ShouldResemble, []string{"hello"})
+			})
+		})
+		Convey("When a test file is edited", func() {
+			So(ioutil.WriteFile(filepath.Join(dir, "hello_test.go"), []byte("package hello_test\n"), 0644), ShouldBeNil)
+			next, err := Take([]string{"hello"}, folder)
+			So(err, ShouldBeNil)
+			Convey("Then nothing has changed", func() {
+				So(Changed(s, next), ShouldBeEmpty)
+			})
+		})
+		Convey("Then a missing package is an error", func() {
+			_, err := Take([]string{"missing"}, folder)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestChanged(t *testing.T) {
+	Convey("Given snapshots of packages", t, func() {
+		now := time.Now()
+		old := Snapshot{
+			"a": {"a.go": File{Size: 1, ModTime: now}},
+			"b": {"b.go": File{Size: 1, ModTime: now}},
+			"c": {"c.go": File{Size: 1, ModTime: now}},
+		}
+		next := Snapshot{
+			"a": {"a.go": File{Size: 1, ModTime: now}},
+			"b": {"b.go": File{Size: 1, ModTime: now.Add(time.Second)}},
+			"d": {"d.go": File{Size: 1, ModTime: now}},
+		}
+		Convey("Then packages edited, added and removed have changed", func() {
+			So(Changed(old, next), ShouldResemble, []string{"b", "c", "d"})
+		})
+	})
+}
+
+func TestWait(t *testing.T) {
+	Convey("Given a watcher over scans of packages being edited", t, func() {
+		now := time.Now()
+		at := func(offset int) Snapshot {
+			return Snapshot{"a": {"a.go": File{Size: 1, ModTime: now.Add(time.Duration(offset) * time.Second)}}}
+		}
+		scans := []Snapshot{at(0), nil, at(1), at(2), at(2), at(3)}
+		calls := 0
+		w := Watcher{Interval: time.Millisecond, Scan: func() (Snapshot, error) {
+			s := at(3)
+			if calls < len(scans) {
+				s = scans[calls]
+			}
+			calls++
+			if s == nil {
+				return nil, errors.New("file being replaced")
+			}
+			return s, nil
+		}}
+		Convey("When it waits for a change", func() {
+			s, changed, ok := w.Wait(at(0), make(chan struct{}))
+			Convey("Then it returns once the scans settle", func() {
+				So(ok, ShouldBeTrue)
+				So(calls, ShouldEqual, 5)
+				So(s, ShouldResemble, at(2))
+				So(changed, ShouldResemble, []string{"a"})
+			})
+		})
+		Convey("When it is stopped", func() {
+			stop := make(chan struct{})
+			close(stop)
+			_, _, ok := w.Wait(at(3), stop)
+			Convey("Then it returns without a change", func() {
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}